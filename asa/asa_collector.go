@@ -0,0 +1,211 @@
+package asa
+
+import (
+	"log"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prefix string = "cisco_asa_"
+
+var (
+	connectionsDesc         *prometheus.Desc
+	connectionsMostUsedDesc *prometheus.Desc
+	xlatesDesc              *prometheus.Desc
+	xlatesMostUsedDesc      *prometheus.Desc
+	failoverEnabledDesc     *prometheus.Desc
+	failoverLinkUpDesc      *prometheus.Desc
+	failoverStateDesc       *prometheus.Desc
+	failoverActiveDesc      *prometheus.Desc
+	failoverInterfaceDesc   *prometheus.Desc
+	vpnActiveDesc           *prometheus.Desc
+	vpnCumulativeDesc       *prometheus.Desc
+	vpnPeakDesc             *prometheus.Desc
+	vpnInactiveDesc         *prometheus.Desc
+	vpnCapacityDesc         *prometheus.Desc
+	vpnLoadDesc             *prometheus.Desc
+	aspDropsDesc            *prometheus.Desc
+)
+
+func init() {
+	l := []string{"target"}
+	connectionsDesc = prometheus.NewDesc(prefix+"connections", "Number of connections in use", l, nil)
+	connectionsMostUsedDesc = prometheus.NewDesc(prefix+"connections_most_used", "Highest number of connections in use since the last reboot or clearing", l, nil)
+	xlatesDesc = prometheus.NewDesc(prefix+"xlates", "Number of NAT translations in use", l, nil)
+	xlatesMostUsedDesc = prometheus.NewDesc(prefix+"xlates_most_used", "Highest number of NAT translations in use since the last reboot or clearing", l, nil)
+	failoverEnabledDesc = prometheus.NewDesc(prefix+"failover_enabled", "Failover is configured (1 On, 0 Off)", l, nil)
+	failoverLinkUpDesc = prometheus.NewDesc(prefix+"failover_link_up", "Status of the failover LAN interface (1 up, 0 down)", append(l, "link"), nil)
+	failoverStateDesc = prometheus.NewDesc(prefix+"failover_state_info", "Failover role and state of this and the other unit", append(l, "host", "role", "state"), nil)
+	failoverActiveDesc = prometheus.NewDesc(prefix+"failover_active", "Unit is the active unit of the failover pair (1 Active, 0 otherwise)", append(l, "host"), nil)
+	failoverInterfaceDesc = prometheus.NewDesc(prefix+"failover_interface_up", "Failover monitoring state of an interface (1 Normal, 0 otherwise)", append(l, "host", "interface", "status"), nil)
+	vpnActiveDesc = prometheus.NewDesc(prefix+"vpn_sessions_active", "Number of active VPN sessions", append(l, "type"), nil)
+	vpnCumulativeDesc = prometheus.NewDesc(prefix+"vpn_sessions_cumulative_total", "Number of VPN sessions since the last reboot or clearing", append(l, "type"), nil)
+	vpnPeakDesc = prometheus.NewDesc(prefix+"vpn_sessions_peak", "Highest number of concurrent VPN sessions", append(l, "type"), nil)
+	vpnInactiveDesc = prometheus.NewDesc(prefix+"vpn_sessions_inactive", "Number of inactive VPN sessions", append(l, "type"), nil)
+	vpnCapacityDesc = prometheus.NewDesc(prefix+"vpn_sessions_capacity", "Maximum number of VPN sessions supported by the device", l, nil)
+	vpnLoadDesc = prometheus.NewDesc(prefix+"vpn_load_percent", "VPN session load of the device in percent", l, nil)
+	aspDropsDesc = prometheus.NewDesc(prefix+"asp_drops_total", "Packets (frame) and flows dropped by the accelerated security path", append(l, "type", "reason", "description"), nil)
+}
+
+type asaCollector struct{}
+
+// NewCollector creates a new collector
+func NewCollector() collector.RPCCollector {
+	return &asaCollector{}
+}
+
+// Name returns the name of the collector
+func (*asaCollector) Name() string {
+	return "ASA"
+}
+
+// Describe describes the metrics
+func (*asaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- connectionsDesc
+	ch <- connectionsMostUsedDesc
+	ch <- xlatesDesc
+	ch <- xlatesMostUsedDesc
+	ch <- failoverEnabledDesc
+	ch <- failoverLinkUpDesc
+	ch <- failoverStateDesc
+	ch <- failoverActiveDesc
+	ch <- failoverInterfaceDesc
+	ch <- vpnActiveDesc
+	ch <- vpnCumulativeDesc
+	ch <- vpnPeakDesc
+	ch <- vpnInactiveDesc
+	ch <- vpnCapacityDesc
+	ch <- vpnLoadDesc
+	ch <- aspDropsDesc
+}
+
+// CollectCounts collects the number of connections and NAT translations
+func (c *asaCollector) CollectCounts(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show conn count")
+	if err != nil {
+		return err
+	}
+	conns, err := ParseCount(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(connectionsDesc, prometheus.GaugeValue, conns.InUse, labelValues...)
+	ch <- prometheus.MustNewConstMetric(connectionsMostUsedDesc, prometheus.GaugeValue, conns.MostUsed, labelValues...)
+
+	out, err = client.RunCommand("show xlate count")
+	if err != nil {
+		return err
+	}
+	xlates, err := ParseCount(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(xlatesDesc, prometheus.GaugeValue, xlates.InUse, labelValues...)
+	ch <- prometheus.MustNewConstMetric(xlatesMostUsedDesc, prometheus.GaugeValue, xlates.MostUsed, labelValues...)
+	return nil
+}
+
+// CollectFailover collects the failover state
+func (c *asaCollector) CollectFailover(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show failover")
+	if err != nil {
+		return err
+	}
+	item, err := ParseFailover(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(failoverEnabledDesc, prometheus.GaugeValue, boolValue(item.Enabled), labelValues...)
+	if !item.Enabled {
+		return nil
+	}
+	if item.HasLink {
+		ch <- prometheus.MustNewConstMetric(failoverLinkUpDesc, prometheus.GaugeValue, boolValue(item.LinkUp), append(labelValues, item.Link)...)
+	}
+	for _, u := range item.Units {
+		ch <- prometheus.MustNewConstMetric(failoverStateDesc, prometheus.GaugeValue, 1, append(labelValues, u.Host, u.Role, u.State)...)
+		ch <- prometheus.MustNewConstMetric(failoverActiveDesc, prometheus.GaugeValue, boolValue(u.State == "Active"), append(labelValues, u.Host)...)
+		for _, i := range u.Interfaces {
+			ch <- prometheus.MustNewConstMetric(failoverInterfaceDesc, prometheus.GaugeValue, boolValue(i.Status == "Normal"), append(labelValues, u.Host, i.Name, i.Status)...)
+		}
+	}
+	return nil
+}
+
+// CollectVPNSessions collects the VPN session counters
+func (c *asaCollector) CollectVPNSessions(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show vpn-sessiondb summary")
+	if err != nil {
+		return err
+	}
+	item, err := ParseVPNSessions(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	for _, t := range item.Types {
+		l := append(labelValues, t.Type)
+		ch <- prometheus.MustNewConstMetric(vpnActiveDesc, prometheus.GaugeValue, t.Active, l...)
+		ch <- prometheus.MustNewConstMetric(vpnCumulativeDesc, prometheus.CounterValue, t.Cumulative, l...)
+		ch <- prometheus.MustNewConstMetric(vpnPeakDesc, prometheus.GaugeValue, t.Peak, l...)
+		if t.HasInactive {
+			ch <- prometheus.MustNewConstMetric(vpnInactiveDesc, prometheus.GaugeValue, t.Inactive, l...)
+		}
+	}
+	if item.HasCapacity {
+		ch <- prometheus.MustNewConstMetric(vpnCapacityDesc, prometheus.GaugeValue, item.Capacity, labelValues...)
+	}
+	if item.HasLoad {
+		ch <- prometheus.MustNewConstMetric(vpnLoadDesc, prometheus.GaugeValue, item.Load, labelValues...)
+	}
+	return nil
+}
+
+// CollectASPDrops collects the drop counters of the accelerated security path
+func (c *asaCollector) CollectASPDrops(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show asp drop")
+	if err != nil {
+		return err
+	}
+	items, err := ParseASPDrops(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	for _, d := range items {
+		ch <- prometheus.MustNewConstMetric(aspDropsDesc, prometheus.CounterValue, d.Count, append(labelValues, d.Type, d.Reason, d.Description)...)
+	}
+	return nil
+}
+
+// Collect collects metrics from Cisco
+func (c *asaCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	if client.OSType != rpc.ASA {
+		// none of the commands exist on routers and switches, don't run them on every scrape
+		return nil
+	}
+	err := c.CollectCounts(client, ch, labelValues)
+	if client.Debug && err != nil {
+		log.Printf("CollectCounts for %s: %s\n", labelValues[0], err.Error())
+	}
+	err = c.CollectFailover(client, ch, labelValues)
+	if client.Debug && err != nil {
+		log.Printf("CollectFailover for %s: %s\n", labelValues[0], err.Error())
+	}
+	err = c.CollectVPNSessions(client, ch, labelValues)
+	if client.Debug && err != nil {
+		log.Printf("CollectVPNSessions for %s: %s\n", labelValues[0], err.Error())
+	}
+	err = c.CollectASPDrops(client, ch, labelValues)
+	if client.Debug && err != nil {
+		log.Printf("CollectASPDrops for %s: %s\n", labelValues[0], err.Error())
+	}
+	return nil
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
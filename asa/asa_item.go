@@ -0,0 +1,57 @@
+package asa
+
+// Count is the current and the highest number of entries of a table (connections, translations)
+type Count struct {
+	InUse    float64
+	MostUsed float64
+}
+
+// Failover is the failover configuration and the state of both units
+type Failover struct {
+	Enabled bool
+	Link    string
+	LinkUp  bool
+	HasLink bool
+	Units   []FailoverUnit
+}
+
+// FailoverUnit is the state of one unit of a failover pair as seen by this unit
+type FailoverUnit struct {
+	Host       string
+	Role       string
+	State      string
+	Interfaces []FailoverInterface
+}
+
+// FailoverInterface is the monitoring state of an interface of a failover unit
+type FailoverInterface struct {
+	Name   string
+	Status string
+}
+
+// VPNSessions is the VPN session summary of the device
+type VPNSessions struct {
+	Types       []VPNSessionType
+	Capacity    float64
+	Load        float64
+	HasCapacity bool
+	HasLoad     bool
+}
+
+// VPNSessionType holds the session counters of one VPN type (AnyConnect, Site-to-Site, ...)
+type VPNSessionType struct {
+	Type        string
+	Active      float64
+	Cumulative  float64
+	Peak        float64
+	Inactive    float64
+	HasInactive bool
+}
+
+// ASPDrop is a drop counter of the accelerated security path
+type ASPDrop struct {
+	Type        string
+	Reason      string
+	Description string
+	Count       float64
+}
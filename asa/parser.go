@@ -0,0 +1,150 @@
+package asa
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/moeinshahcheraghi/cisco_exporter/util"
+)
+
+// ParseCount parses the output of 'show conn count' and 'show xlate count'
+func ParseCount(ostype string, output string) (Count, error) {
+	if ostype != rpc.ASA {
+		return Count{}, errors.New("'show conn count' is not implemented for " + ostype)
+	}
+	countRegexp := regexp.MustCompile(`^\s*(\d+) in use, (\d+) most used`)
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := countRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		return Count{
+			InUse:    util.Str2float64(matches[1]),
+			MostUsed: util.Str2float64(matches[2]),
+		}, nil
+	}
+	return Count{}, errors.New("Count not found")
+}
+
+// ParseFailover parses the output of 'show failover'
+func ParseFailover(ostype string, output string) (Failover, error) {
+	if ostype != rpc.ASA {
+		return Failover{}, errors.New("'show failover' is not implemented for " + ostype)
+	}
+	enabledRegexp := regexp.MustCompile(`^Failover (On|Off)\s*$`)
+	linkRegexp := regexp.MustCompile(`^Failover LAN Interface: (\S+) .*\((\w+)\)\s*$`)
+	hostRegexp := regexp.MustCompile(`^(This|Other) host: (\S+) - (.+?)\s*$`)
+	interfaceRegexp := regexp.MustCompile(`^Interface (\S+) \(.*?\): (.+?)(?: \(.*\))?$`)
+
+	item := Failover{}
+	found := false
+	var unit *FailoverUnit
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Stateful Failover") {
+			// statistics of the state link follow, nothing of a unit anymore
+			unit = nil
+			continue
+		}
+		switch {
+		case enabledRegexp.MatchString(line):
+			item.Enabled = enabledRegexp.FindStringSubmatch(line)[1] == "On"
+			found = true
+		case linkRegexp.MatchString(line):
+			matches := linkRegexp.FindStringSubmatch(line)
+			item.Link = matches[1]
+			item.LinkUp = matches[2] == "up"
+			item.HasLink = true
+		case hostRegexp.MatchString(line):
+			matches := hostRegexp.FindStringSubmatch(line)
+			item.Units = append(item.Units, FailoverUnit{
+				Host:  strings.ToLower(matches[1]),
+				Role:  matches[2],
+				State: matches[3],
+			})
+			unit = &item.Units[len(item.Units)-1]
+		case interfaceRegexp.MatchString(line) && unit != nil:
+			matches := interfaceRegexp.FindStringSubmatch(line)
+			unit.Interfaces = append(unit.Interfaces, FailoverInterface{Name: matches[1], Status: matches[2]})
+		}
+	}
+	if !found {
+		return Failover{}, errors.New("Failover state not found")
+	}
+	return item, nil
+}
+
+// ParseVPNSessions parses the output of 'show vpn-sessiondb summary'
+func ParseVPNSessions(ostype string, output string) (VPNSessions, error) {
+	if ostype != rpc.ASA {
+		return VPNSessions{}, errors.New("'show vpn-sessiondb summary' is not implemented for " + ostype)
+	}
+	// only the VPN types are parsed, the indented protocol lines below them add up to the same sessions
+	typeRegexp := regexp.MustCompile(`^(\S.*?)\s*:\s*(\d+)\s*:\s*(\d+)\s*:\s*(\d+)(?:\s*:\s*(\d+))?\s*$`)
+	capacityRegexp := regexp.MustCompile(`^Device Total VPN Capacity\s*:\s*(\d+)`)
+	loadRegexp := regexp.MustCompile(`^Device Load\s*:\s*(\d+)%`)
+
+	item := VPNSessions{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		// the tunnels of the sessions (IKEv2, IPsec, SSL-Tunnel, ...) are listed with the same columns
+		if strings.HasPrefix(strings.TrimSpace(line), "Tunnels Summary") {
+			break
+		}
+		if matches := capacityRegexp.FindStringSubmatch(line); matches != nil {
+			item.Capacity, item.HasCapacity = util.Str2float64(matches[1]), true
+		} else if matches := loadRegexp.FindStringSubmatch(line); matches != nil {
+			item.Load, item.HasLoad = util.Str2float64(matches[1]), true
+		} else if matches := typeRegexp.FindStringSubmatch(line); matches != nil {
+			t := VPNSessionType{
+				Type:       matches[1],
+				Active:     util.Str2float64(matches[2]),
+				Cumulative: util.Str2float64(matches[3]),
+				Peak:       util.Str2float64(matches[4]),
+			}
+			if matches[5] != "" {
+				t.Inactive, t.HasInactive = util.Str2float64(matches[5]), true
+			}
+			item.Types = append(item.Types, t)
+		}
+	}
+	return item, nil
+}
+
+// ParseASPDrops parses the output of 'show asp drop'
+func ParseASPDrops(ostype string, output string) ([]ASPDrop, error) {
+	if ostype != rpc.ASA {
+		return nil, errors.New("'show asp drop' is not implemented for " + ostype)
+	}
+	sectionRegexp := regexp.MustCompile(`^(Frame|Flow) drop:\s*$`)
+	dropRegexp := regexp.MustCompile(`^\s+(.+?) \(([\w-]+)\)\s+(\d+)\s*$`)
+
+	items := []ASPDrop{}
+	section := ""
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if matches := sectionRegexp.FindStringSubmatch(line); matches != nil {
+			section = strings.ToLower(matches[1])
+			continue
+		}
+		if section == "" {
+			continue
+		}
+		matches := dropRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		items = append(items, ASPDrop{
+			Type:        section,
+			Reason:      matches[2],
+			Description: matches[1],
+			Count:       util.Str2float64(matches[3]),
+		})
+	}
+	return items, nil
+}
@@ -16,11 +16,15 @@ var (
 	scrapeCollectorDurationDesc *prometheus.Desc
 	scrapeDurationDesc          *prometheus.Desc
 	upDesc                      *prometheus.Desc
+	scrapeErrorDesc             *prometheus.Desc
+	credentialProfileDesc       *prometheus.Desc
 )
 
 
 func init() {
 	upDesc = prometheus.NewDesc(prefix+"up", "Scrape of target was successful", []string{"target"}, nil)
+	scrapeErrorDesc = prometheus.NewDesc(prefix+"scrape_error_info", "Reason the connection to the target failed (auth_failed, enable_rejected, credentials_unavailable, timeout or connect_failed)", []string{"target", "reason"}, nil)
+	credentialProfileDesc = prometheus.NewDesc(prefix+"credential_profile_info", "Credential profile the target accepted", []string{"target", "profile"}, nil)
 	scrapeDurationDesc = prometheus.NewDesc(prefix+"collector_duration_seconds", "Duration of a collector scrape for one target", []string{"target"}, nil)
 	scrapeCollectorDurationDesc = prometheus.NewDesc(prefix+"collect_duration_seconds", "Duration of a scrape by collector and target", []string{"target", "collector"}, nil)
 }
@@ -41,6 +45,8 @@ func newCiscoCollector(devices []*connector.Device) *ciscoCollector {
 // Describe implements prometheus.Collector interface
 func (c *ciscoCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- upDesc
+	ch <- scrapeErrorDesc
+	ch <- credentialProfileDesc
 	ch <- scrapeDurationDesc
 	ch <- scrapeCollectorDurationDesc
 
@@ -73,16 +79,29 @@ func (c *ciscoCollector) collectForHost(device *connector.Device, ch chan<- prom
 
     conn, err := connector.NewSSSHConnection(device, cfg)
     if err != nil {
-        log.Errorln(err)
+        log.Errorln(device.Host + ": " + err.Error())
         ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 0, l...)
+        ch <- prometheus.MustNewConstMetric(scrapeErrorDesc, prometheus.GaugeValue, 1, append(l, connector.ErrorReason(err))...)
         return
     }
     defer conn.Close()
 
     ch <- prometheus.MustNewConstMetric(upDesc, prometheus.GaugeValue, 1, l...)
+    if conn.Profile != "" {
+        ch <- prometheus.MustNewConstMetric(credentialProfileDesc, prometheus.GaugeValue, 1, append(l, conn.Profile)...)
+    }
 
     client := rpc.NewClient(conn, cfg.Debug)
-    err = client.Identify()
+    client.SetCommandOverrides(cfg.Commands)
+    if dc := device.DeviceConfig; dc != nil && dc.OSType != nil {
+        platform := ""
+        if dc.Platform != nil {
+            platform = *dc.Platform
+        }
+        err = client.IdentifyAs(*dc.OSType, platform)
+    } else {
+        err = client.Identify()
+    }
     if err != nil {
         log.Errorln(device.Host + ": " + err.Error())
         return
@@ -16,6 +16,11 @@ import (
     "github.com/moeinshahcheraghi/cisco_exporter/vlan"
     "github.com/moeinshahcheraghi/cisco_exporter/qos"
     "github.com/moeinshahcheraghi/cisco_exporter/acl"
+    "github.com/moeinshahcheraghi/cisco_exporter/inventory"
+    "github.com/moeinshahcheraghi/cisco_exporter/module"
+    "github.com/moeinshahcheraghi/cisco_exporter/redundancy"
+    "github.com/moeinshahcheraghi/cisco_exporter/asa"
+    "github.com/moeinshahcheraghi/cisco_exporter/wlc"
 
 )
 
@@ -46,18 +51,35 @@ func (c *collectors) initCollectorsForDevice(device *connector.Device) {
 	c.addCollectorIfEnabledForDevice(device, "bgp", f.BGP, bgp.NewCollector)
 	c.addCollectorIfEnabledForDevice(device, "environment", f.Environment, environment.NewCollector)
 	c.addCollectorIfEnabledForDevice(device, "facts", f.Facts, facts.NewCollector)
+	c.addCollectorIfEnabledForDevice(device, "factsProcesses", f.FactsProcesses, func() collector.RPCCollector {
+		return facts.NewProcessCollector(c.cfg.ProcessTopN)
+	})
 	c.addCollectorIfEnabledForDevice(device, "interfaces", f.Interfaces, interfaces.NewCollector)
 	c.addCollectorIfEnabledForDevice(device, "optics", f.Optics, optics.NewCollector)
 	c.addCollectorIfEnabledForDevice(device, "stackport", f.StackPort, stackport.NewCollector) 
+	c.addCollectorIfEnabledForDevice(device, "stackMembers", f.StackMembers, stackport.NewMemberCollector)
 	c.addCollectorIfEnabledForDevice(device, "tablesARP", f.TablesARP, tables.NewARPCollector)
+	c.addCollectorIfEnabledForDevice(device, "tablesND", f.TablesND, tables.NewNDCollector)
 	c.addCollectorIfEnabledForDevice(device, "tablesMAC", f.TablesMAC, tables.NewMACCollector)
-	c.addCollectorIfEnabledForDevice(device, "tablesRouteIPv4", f.TablesRouteIPv4, tables.NewRouteIPv4Collector)
-	c.addCollectorIfEnabledForDevice(device, "tablesRouteIPv6", f.TablesRouteIPv6, tables.NewRouteIPv6Collector)
+	c.addCollectorIfEnabledForDevice(device, "tablesMACEntries", f.TablesMACEntries, tables.NewMACEntriesCollector)
+	c.addCollectorIfEnabledForDevice(device, "tablesRouteIPv4", f.TablesRouteIPv4, func() collector.RPCCollector {
+		return tables.NewRouteIPv4Collector(c.cfg.RouteWatch)
+	})
+	c.addCollectorIfEnabledForDevice(device, "tablesRouteIPv6", f.TablesRouteIPv6, func() collector.RPCCollector {
+		return tables.NewRouteIPv6Collector(c.cfg.RouteWatch)
+	})
 	c.addCollectorIfEnabledForDevice(device, "uptime", f.Uptime, uptime.NewCollector)
     c.addCollectorIfEnabledForDevice(device, "stp", f.STP, stp.NewCollector)
     c.addCollectorIfEnabledForDevice(device, "vlan", f.VLAN, vlan.NewCollector)
     c.addCollectorIfEnabledForDevice(device, "qos", f.QoS, qos.NewCollector)
+    c.addCollectorIfEnabledForDevice(device, "qosQueues", f.QoSQueues, qos.NewQueueCollector)
     c.addCollectorIfEnabledForDevice(device, "acl", f.ACL, acl.NewCollector)
+    c.addCollectorIfEnabledForDevice(device, "inventory", f.Inventory, inventory.NewCollector)
+    c.addCollectorIfEnabledForDevice(device, "module", f.Module, module.NewCollector)
+    c.addCollectorIfEnabledForDevice(device, "redundancy", f.Redundancy, redundancy.NewCollector)
+    c.addCollectorIfEnabledForDevice(device, "asa", f.ASA, asa.NewCollector)
+    c.addCollectorIfEnabledForDevice(device, "wlc", f.WLC, wlc.NewCollector)
+    c.addCollectorIfEnabledForDevice(device, "wlcChannels", f.WLCChannels, wlc.NewChannelCollector)
 }
 
 func (c *collectors) addCollectorIfEnabledForDevice(device *connector.Device, key string, enabled *bool, newCollector func() collector.RPCCollector) {
@@ -9,44 +9,93 @@ import (
 )
 
 type Config struct {
-	Debug         bool            `yaml:"debug"`
-	LegacyCiphers bool            `yaml:"legacy_ciphers,omitempty"`
-	Timeout       int             `yaml:"timeout,omitempty"`
-	BatchSize     int             `yaml:"batch_size,omitempty"`
-	Username      string          `yaml:"username,omitempty"`
-	Password      string          `yaml:"Password,omitempty"`
-	KeyFile       string          `yaml:"key_file,omitempty"`
-	Devices       []*DeviceConfig `yaml:"devices,omitempty"`
-	Features      *FeatureConfig  `yaml:"features,omitempty"`
+	Debug          bool                         `yaml:"debug"`
+	LegacyCiphers  bool                         `yaml:"legacy_ciphers,omitempty"`
+	Timeout        int                          `yaml:"timeout,omitempty"`
+	BatchSize      int                          `yaml:"batch_size,omitempty"`
+	Username       string                       `yaml:"username,omitempty"`
+	Password       string                       `yaml:"password,omitempty"`
+	LegacyPassword string                       `yaml:"Password,omitempty"` // key read before it was fixed to lowercase
+	KeyFile        string                       `yaml:"key_file,omitempty"`
+	EnablePassword string                       `yaml:"enable_password,omitempty"`
+	Devices        []*DeviceConfig              `yaml:"devices,omitempty"`
+	Features       *FeatureConfig               `yaml:"features,omitempty"`
+	RouteWatch     []*RouteWatchConfig          `yaml:"route_watchlist,omitempty"`
+	ProcessTopN    int                          `yaml:"process_top_n,omitempty"`
+	Commands       map[string]map[string]string `yaml:"commands,omitempty"`
+	JumpHosts      []*JumpHostConfig            `yaml:"jump_hosts,omitempty"`
+	Proxy          string                       `yaml:"proxy,omitempty"`
+	SecretRefresh  int                          `yaml:"secret_refresh_interval,omitempty"`
+	Profiles       map[string]*CredentialConfig `yaml:"credential_profiles,omitempty"`
+	Credentials    []string                     `yaml:"credentials,omitempty"`
 }
 
 type DeviceConfig struct {
-	Host          string         `yaml:"host"`
-	Username      *string        `yaml:"username,omitempty"`
-	Password      *string        `yaml:"password,omitempty"`
-	KeyFile       *string        `yaml:"key_file,omitempty"`
-	LegacyCiphers *bool          `yaml:"legacy_ciphers,omitempty"`
-	Timeout       *int           `yaml:"timeout,omitempty"`
-	BatchSize     *int           `yaml:"batch_size,omitempty"`
-	Features      *FeatureConfig `yaml:"features,omitempty"`
+	Host           string            `yaml:"host"`
+	Username       *string           `yaml:"username,omitempty"`
+	Password       *string           `yaml:"password,omitempty"`
+	KeyFile        *string           `yaml:"key_file,omitempty"`
+	EnablePassword *string           `yaml:"enable_password,omitempty"`
+	LegacyCiphers  *bool             `yaml:"legacy_ciphers,omitempty"`
+	Timeout        *int              `yaml:"timeout,omitempty"`
+	BatchSize      *int              `yaml:"batch_size,omitempty"`
+	OSType         *string           `yaml:"os_type,omitempty"`
+	Platform       *string           `yaml:"platform,omitempty"`
+	JumpHosts      []*JumpHostConfig `yaml:"jump_hosts,omitempty"`
+	Proxy          *string           `yaml:"proxy,omitempty"`
+	Credentials    []string          `yaml:"credentials,omitempty"`
+	Features       *FeatureConfig    `yaml:"features,omitempty"`
+}
+
+// CredentialConfig is a named set of credentials devices refer to, tried in the order the device lists them
+type CredentialConfig struct {
+	Username       string `yaml:"username,omitempty"`
+	Password       string `yaml:"password,omitempty"`
+	KeyFile        string `yaml:"key_file,omitempty"`
+	EnablePassword string `yaml:"enable_password,omitempty"`
+}
+
+// JumpHostConfig is an SSH bastion the connection to a device is tunnelled through
+type JumpHostConfig struct {
+	Host     string `yaml:"host"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+}
+
+// RouteWatchConfig is a critical prefix whose presence, next-hop and protocol are exported
+type RouteWatchConfig struct {
+	Prefix string `yaml:"prefix"`
+	VRF    string `yaml:"vrf,omitempty"`
 }
 
 type FeatureConfig struct {
-	BGP         *bool `yaml:"bgp,omitempty"`
-	Environment *bool `yaml:"environment,omitempty"`
-	Facts       *bool `yaml:"facts,omitempty"`
-	Interfaces  *bool `yaml:"interfaces,omitempty"`
-	Optics      *bool `yaml:"optics,omitempty"`
-	StackPort   *bool `yaml:"stack_port,omitempty"`
-	TablesARP       *bool `yaml:"tables_arp,omitempty"`
-	TablesMAC       *bool `yaml:"tables_mac,omitempty"`
-	TablesRouteIPv4 *bool `yaml:"tables_route_ipv4,omitempty"`
-	TablesRouteIPv6 *bool `yaml:"tables_route_ipv6,omitempty"`
+	BGP            *bool `yaml:"bgp,omitempty"`
+	Environment    *bool `yaml:"environment,omitempty"`
+	Facts          *bool `yaml:"facts,omitempty"`
+	FactsProcesses *bool `yaml:"facts_processes,omitempty"`
+	Interfaces     *bool `yaml:"interfaces,omitempty"`
+	Optics         *bool `yaml:"optics,omitempty"`
+	StackPort      *bool `yaml:"stack_port,omitempty"`
+	StackMembers   *bool `yaml:"stack_members,omitempty"`
+	TablesARP        *bool `yaml:"tables_arp,omitempty"`
+	TablesND         *bool `yaml:"tables_nd,omitempty"`
+	TablesMAC        *bool `yaml:"tables_mac,omitempty"`
+	TablesMACEntries *bool `yaml:"tables_mac_entries,omitempty"`
+	TablesRouteIPv4  *bool `yaml:"tables_route_ipv4,omitempty"`
+	TablesRouteIPv6  *bool `yaml:"tables_route_ipv6,omitempty"`
     Uptime      *bool `yaml:"uptime,omitempty"`
     STP         *bool `yaml:"stp,omitempty"`
     VLAN        *bool `yaml:"vlan,omitempty"`
     QoS         *bool `yaml:"qos,omitempty"`
+    QoSQueues   *bool `yaml:"qos_queues,omitempty"`
     ACL         *bool `yaml:"acl,omitempty"`
+    Inventory   *bool `yaml:"inventory,omitempty"`
+    Module      *bool `yaml:"module,omitempty"`
+    Redundancy  *bool `yaml:"redundancy,omitempty"`
+    ASA         *bool `yaml:"asa,omitempty"`
+    WLC         *bool `yaml:"wlc,omitempty"`
+    WLCChannels *bool `yaml:"wlc_channel_utilization,omitempty"`
 }
 
 func New() *Config {
@@ -69,6 +118,10 @@ func Load(reader io.Reader) (*Config, error) {
 		return nil, err
 	}
 
+	if c.Password == "" {
+		c.Password = c.LegacyPassword
+	}
+
 	for _, d := range c.Devices {
 		if d.Features == nil {
 			continue
@@ -82,6 +135,9 @@ func Load(reader io.Reader) (*Config, error) {
 		if d.Features.Facts == nil {
 			d.Features.Facts = c.Features.Facts
 		}
+		if d.Features.FactsProcesses == nil {
+			d.Features.FactsProcesses = c.Features.FactsProcesses
+		}
 		if d.Features.Interfaces == nil {
 			d.Features.Interfaces = c.Features.Interfaces
 		}
@@ -91,6 +147,36 @@ func Load(reader io.Reader) (*Config, error) {
 		if d.Features.StackPort == nil {
 			d.Features.StackPort = c.Features.StackPort
 		}
+		if d.Features.StackMembers == nil {
+			d.Features.StackMembers = c.Features.StackMembers
+		}
+		if d.Features.TablesND == nil {
+			d.Features.TablesND = c.Features.TablesND
+		}
+		if d.Features.TablesMACEntries == nil {
+			d.Features.TablesMACEntries = c.Features.TablesMACEntries
+		}
+		if d.Features.QoSQueues == nil {
+			d.Features.QoSQueues = c.Features.QoSQueues
+		}
+		if d.Features.Inventory == nil {
+			d.Features.Inventory = c.Features.Inventory
+		}
+		if d.Features.Module == nil {
+			d.Features.Module = c.Features.Module
+		}
+		if d.Features.Redundancy == nil {
+			d.Features.Redundancy = c.Features.Redundancy
+		}
+		if d.Features.ASA == nil {
+			d.Features.ASA = c.Features.ASA
+		}
+		if d.Features.WLC == nil {
+			d.Features.WLC = c.Features.WLC
+		}
+		if d.Features.WLCChannels == nil {
+			d.Features.WLCChannels = c.Features.WLCChannels
+		}
 	}
 
 	return c, nil
@@ -101,6 +187,8 @@ func (c *Config) setDefaultValues() {
 	c.LegacyCiphers = false
 	c.Timeout = 5
 	c.BatchSize = 10000
+	c.ProcessTopN = 10
+	c.SecretRefresh = 300
 
 	f := c.Features
 	bgp := true
@@ -109,16 +197,24 @@ func (c *Config) setDefaultValues() {
 	f.Environment = &environment
 	facts := true
 	f.Facts = &facts
+	factsProcesses := false
+	f.FactsProcesses = &factsProcesses
 	interfaces := true
 	f.Interfaces = &interfaces
 	optics := true
 	f.Optics = &optics
 	stackPort := true
 	f.StackPort = &stackPort
+	stackMembers := true
+	f.StackMembers = &stackMembers
 	tablesARP := true
 	c.Features.TablesARP = &tablesARP
+	tablesND := true
+	c.Features.TablesND = &tablesND
 	tablesMAC := true
 	c.Features.TablesMAC = &tablesMAC
+	tablesMACEntries := false
+	c.Features.TablesMACEntries = &tablesMACEntries
 	tablesRouteIPv4 := true
 	c.Features.TablesRouteIPv4 = &tablesRouteIPv4
 	tablesRouteIPv6 := true
@@ -131,8 +227,22 @@ func (c *Config) setDefaultValues() {
     c.Features.VLAN = &vlan
     qos := true
     c.Features.QoS = &qos
+    qosQueues := false
+    c.Features.QoSQueues = &qosQueues
     acl := true
     c.Features.ACL = &acl
+    inventory := true
+    c.Features.Inventory = &inventory
+    module := true
+    c.Features.Module = &module
+    redundancy := true
+    c.Features.Redundancy = &redundancy
+    asa := true
+    c.Features.ASA = &asa
+    wlc := true
+    c.Features.WLC = &wlc
+    wlcChannels := false
+    c.Features.WLCChannels = &wlcChannels
 
 }
 
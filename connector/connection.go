@@ -1,7 +1,6 @@
 package connector
 
 import (
-	"bufio"
 	"io"
 	"io/ioutil"
 	"regexp"
@@ -9,20 +8,49 @@ import (
 	"time"
 	"log"
 	"github.com/moeinshahcheraghi/cisco_exporter/config"
+	"github.com/moeinshahcheraghi/cisco_exporter/secret"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
 )
 
+// DefaultPagination is the command disabling paging sent right after connecting
+const DefaultPagination = "terminal length 0"
+
+var (
+	// defaultPrompt is used until the prompt is learned, it also accepts the '>' prompt of AireOS and FTD
+	defaultPrompt = regexp.MustCompile(`.*[#>]\s?$`)
+	// userPrompt matches the user EXEC prompt of IOS, NX-OS and ASA, but not the bare '>' of FTD or the one of AireOS
+	userPrompt     = regexp.MustCompile(`^[^\s(]\S*>$`)
+	passwordPrompt = regexp.MustCompile(`(?i)password:\s?$`)
+	// loginPrompt matches the second login AireOS controllers ask for in the shell
+	loginPrompt = regexp.MustCompile(`(?i)^user(?:name)?:\s?$`)
+	// pagerRegexp matches the pagers of IOS, NX-OS and ASA ('--More--'), AireOS ('--More-- or (q)uit') and the ones of some IOS XE releases
+	pagerRegexp = regexp.MustCompile(`^\s*(?:--More--(?: or \(q\)uit)?|<--- More --->)\s*$`)
+	// terminalRegexp matches the ANSI escape sequences and backspaces used by pagers to erase their prompt
+	terminalRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]|\x08+ *\x08*`)
+)
+
 // SSHConnection encapsulates the connection to the device
 type SSHConnection struct {
-	client       *ssh.Client
-	Host         string
-	stdin        io.WriteCloser
-	stdout       io.Reader
-	session      *ssh.Session
-	batchSize    int
-	clientConfig *ssh.ClientConfig
-	Debug        bool // فیلد جدید برای پرچم دیباگ
+	client         *ssh.Client
+	Host           string
+	stdin          io.WriteCloser
+	stdout         io.Reader
+	session        *ssh.Session
+	batchSize      int
+	clientConfig   *ssh.ClientConfig
+	prompt         *regexp.Regexp
+	learned        bool
+	enablePassword string
+	password       string
+	chunks         chan string
+	readErr        error
+	done           chan struct{}
+	jumpHosts      []*config.JumpHostConfig
+	proxy          string
+	// Profile is the name of the credential profile the device accepted
+	Profile        string
+	Debug          bool // فیلد جدید برای پرچم دیباگ
 }
 
 // NewSSSHConnection connects to device
@@ -44,37 +72,98 @@ func NewSSSHConnection(device *Device, cfg *config.Config) (*SSHConnection, erro
 		timeout = *deviceConfig.Timeout
 	}
 
-	sshConfig := &ssh.ClientConfig{
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-		Timeout:         time.Duration(timeout) * time.Second,
+	enablePassword := cfg.EnablePassword
+	if deviceConfig.EnablePassword != nil {
+		enablePassword = *deviceConfig.EnablePassword
 	}
-	if legacyCiphers {
-		sshConfig.SetDefaults()
-		sshConfig.Ciphers = append(sshConfig.Ciphers, "aes128-cbc", "3des-cbc")
+
+	jumpHosts := cfg.JumpHosts
+	if deviceConfig.JumpHosts != nil {
+		jumpHosts = deviceConfig.JumpHosts
 	}
 
-	device.Auth(sshConfig)
+	proxy := cfg.Proxy
+	if deviceConfig.Proxy != nil {
+		proxy = *deviceConfig.Proxy
+	}
 
 	c := &SSHConnection{
-		Host:         device.Host + ":" + device.Port,
-		batchSize:    batchSize,
-		clientConfig: sshConfig,
-		Debug:        cfg.Debug, // مقداردهی فیلد Debug از cfg
+		Host:      device.Host + ":" + device.Port,
+		batchSize: batchSize,
+		prompt:    defaultPrompt,
+		jumpHosts: jumpHosts,
+		proxy:     proxy,
+		Debug:     cfg.Debug, // مقداردهی فیلد Debug از cfg
 	}
 
-	err := c.Connect()
-	if err != nil {
-		return nil, err
+	err := errors.New("no valid authentication method available")
+	var rejected []string
+	for _, cred := range acceptedProfiles.order(c.Host, device.Credentials) {
+		sshConfig := &ssh.ClientConfig{
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			Timeout:         time.Duration(timeout) * time.Second,
+		}
+		if legacyCiphers {
+			sshConfig.SetDefaults()
+			sshConfig.Ciphers = append(sshConfig.Ciphers, "aes128-cbc", "3des-cbc")
+		}
+		if err := cred.Auth(sshConfig); err != nil {
+			return nil, &credentialsError{errors.Wrap(err, "could not resolve credentials")}
+		}
+		c.clientConfig = sshConfig
+
+		password := enablePassword
+		if cred.EnablePassword != "" {
+			password = cred.EnablePassword
+		}
+		c.enablePassword, err = secret.Resolve(password)
+		if err != nil {
+			return nil, &credentialsError{errors.Wrap(err, "could not resolve enable password")}
+		}
+		c.password, err = secret.Resolve(cred.Password)
+		if err != nil {
+			return nil, &credentialsError{errors.Wrap(err, "could not resolve password")}
+		}
+		c.Profile = cred.Profile
+
+		err = c.Connect()
+		if _, ok := err.(*authError); ok {
+			if c.Debug {
+				log.Printf("Credential profile '%s' rejected by %s\n", cred.Profile, c.Host)
+			}
+			rejected = append(rejected, cred.Profile)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		acceptedProfiles.set(c.Host, cred.Profile)
+		return c, nil
 	}
 
-	return c, nil
+	if len(rejected) > 1 {
+		return nil, errors.Wrapf(err, "all credential profiles rejected (%s)", strings.Join(rejected, ", "))
+	}
+	return nil, err
 }
 
 // Connect connects to the device
 func (c *SSHConnection) Connect() error {
+	var jump *ssh.Client
 	var err error
-	c.client, err = ssh.Dial("tcp", c.Host, c.clientConfig)
+	if len(c.jumpHosts) > 0 {
+		// the bastions stay connected for the other devices behind them
+		jump, err = jumpHosts.dial(c.jumpHosts, c.proxy, c.clientConfig.User, c.clientConfig.Timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.client, err = dialThrough(jump, c.proxy, c.Host, c.clientConfig)
 	if err != nil {
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			return &authError{err}
+		}
 		return err
 	}
 
@@ -92,36 +181,153 @@ func (c *SSHConnection) Connect() error {
 	session.RequestPty("vt100", 0, 2000, modes)
 	session.Shell()
 	c.session = session
+	c.startReader()
 
-	c.RunCommand("")
-	c.RunCommand("terminal length 0")
+	if err := c.learnPrompt(); err != nil {
+		c.Close()
+		return err
+	}
+	c.RunCommand(DefaultPagination)
 
 	return nil
 }
 
-type result struct {
-	output string
-	err    error
+// learnPrompt waits for the login banner to pass and derives the prompt from the hostname the device shows.
+// If the device is in user EXEC mode, it escalates to privileged EXEC mode with the enable password.
+func (c *SSHConnection) learnPrompt() error {
+	out, err := c.readUntil(regexp.MustCompile(loginPrompt.String() + "|" + c.prompt.String()))
+	if err != nil && err != errTimeout {
+		return err
+	}
+	if err == nil && loginPrompt.MatchString(lastLine(clean(out))) {
+		if err := c.login(); err != nil {
+			return err
+		}
+	}
+	// a banner may end a line with '#' or '>', so wait for the device to finish its greeting
+	c.settle()
+
+	// the prompt is only trusted once seen twice in a row
+	prompt := ""
+	for i := 0; i < 3; i++ {
+		io.WriteString(c.stdin, "\n")
+		out, err := c.readUntil(c.prompt)
+		if err == errTimeout {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line := lastLine(clean(out))
+		if line == prompt {
+			break
+		}
+		prompt = line
+	}
+	if prompt == "" {
+		if c.Debug {
+			log.Printf("Could not learn prompt of %s\n", c.Host)
+		}
+		return nil
+	}
+	c.setLearnedPrompt(prompt)
+	if c.Debug {
+		log.Printf("Learned prompt of %s: %s\n", c.Host, prompt)
+	}
+
+	if !userPrompt.MatchString(prompt) {
+		return nil
+	}
+	if c.enablePassword == "" {
+		if c.Debug {
+			log.Printf("%s is in user EXEC mode and no enable password is configured\n", c.Host)
+		}
+		return nil
+	}
+	return c.enable()
 }
 
-// RunCommand runs a command against the device with enhanced timeout logging
+// login answers the 'User:' and 'Password:' prompts of AireOS controllers with the credentials of the SSH login
+func (c *SSHConnection) login() error {
+	io.WriteString(c.stdin, c.clientConfig.User+"\n")
+	if _, err := c.readUntil(passwordPrompt); err != nil {
+		return errors.Wrap(err, "login failed")
+	}
+	io.WriteString(c.stdin, c.password+"\n")
+
+	out, err := c.readUntil(regexp.MustCompile(loginPrompt.String() + "|" + c.prompt.String()))
+	if err != nil {
+		return errors.Wrap(err, "login failed")
+	}
+	if loginPrompt.MatchString(lastLine(clean(out))) {
+		return &authError{errors.New("login failed: credentials rejected")}
+	}
+	return nil
+}
+
+// setLearnedPrompt accepts the hostname of the learned prompt in any mode, e.g. 'switch>', 'switch#' or 'switch(config)#'
+func (c *SSHConnection) setLearnedPrompt(prompt string) {
+	hostname := strings.TrimRight(prompt, "#> ")
+	c.prompt = regexp.MustCompile(`^` + regexp.QuoteMeta(hostname) + `(?:\([^)]*\))?\s?[#>]\s?$`)
+	c.learned = true
+}
+
+// enable escalates to privileged EXEC mode
+func (c *SSHConnection) enable() error {
+	waitFor := regexp.MustCompile(passwordPrompt.String() + "|" + c.prompt.String())
+	io.WriteString(c.stdin, "enable\n")
+	out, err := c.readUntil(waitFor)
+	if err != nil {
+		return errors.Wrap(err, "enable failed")
+	}
+	if passwordPrompt.MatchString(lastLine(clean(out))) {
+		io.WriteString(c.stdin, c.enablePassword+"\n")
+		out, err = c.readUntil(waitFor)
+		if err != nil {
+			return errors.Wrap(err, "enable failed")
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(clean(out)), "\n")
+	if strings.HasSuffix(lines[len(lines)-1], "#") {
+		return nil
+	}
+	// the device asks for the password again or prints the reason (e.g. '% Access denied', '% Bad secrets')
+	reason := "enable password rejected"
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "%") {
+			reason = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "%"))
+		}
+	}
+	return &enableError{reason}
+}
+
+// SetPrompt sets the regular expression matching the prompt that terminates the output of a command.
+// It is ignored once the prompt was learned at login.
+func (c *SSHConnection) SetPrompt(prompt *regexp.Regexp) {
+	if prompt != nil && !c.learned {
+		c.prompt = prompt
+	}
+}
+
+var errTimeout = errors.New("Timeout reached")
+
+// settleTime is the silence after which the device is considered done sending its greeting
+const settleTime = 300 * time.Millisecond
+
+// RunCommand runs a command against the device and returns its output without the echoed command and the prompt
 func (c *SSHConnection) RunCommand(cmd string) (string, error) {
-	buf := bufio.NewReader(c.stdout)
+	// output of an earlier command which ran into the timeout must not end up in this one
+	c.drain()
 	io.WriteString(c.stdin, cmd+"\n")
 
-	outputChan := make(chan result)
-	go func() {
-		c.readln(outputChan, cmd, buf)
-	}()
-	select {
-	case res := <-outputChan:
-		return res.output, res.err
-	case <-time.After(c.clientConfig.Timeout):
-		if c.Debug { // استفاده از c.Debug به جای c.clientConfig.Debug
+	out, err := c.readUntil(c.prompt)
+	if err != nil {
+		if err == errTimeout && c.Debug {
 			log.Printf("Timeout reached for command '%s' on %s\n", cmd, c.Host)
 		}
-		return "", errors.New("Timeout reached")
+		return "", err
 	}
+	return c.stripEchoAndPrompt(cmd, clean(out)), nil
 }
 
 // Close closes connection
@@ -133,6 +339,13 @@ func (c *SSHConnection) Close() {
 	if c.session != nil {
 		c.session.Close()
 	}
+	if c.done != nil {
+		select {
+		case <-c.done:
+		default:
+			close(c.done)
+		}
+	}
 }
 
 func loadPrivateKey(r io.Reader) (ssh.AuthMethod, error) {
@@ -149,20 +362,110 @@ func loadPrivateKey(r io.Reader) (ssh.AuthMethod, error) {
 	return ssh.PublicKeys(key), nil
 }
 
-func (c *SSHConnection) readln(ch chan result, cmd string, r io.Reader) {
-	re := regexp.MustCompile(`.+#\s?$`)
-	buf := make([]byte, c.batchSize)
-	loadStr := ""
+// startReader reads the output of the device in the background until the session ends
+func (c *SSHConnection) startReader() {
+	c.chunks = make(chan string, 64)
+	c.done = make(chan struct{})
+	go func() {
+		buf := make([]byte, c.batchSize)
+		for {
+			n, err := c.stdout.Read(buf)
+			if n > 0 {
+				select {
+				case c.chunks <- string(buf[:n]):
+				case <-c.done:
+					return
+				}
+			}
+			if err != nil {
+				c.readErr = err
+				close(c.chunks)
+				return
+			}
+		}
+	}()
+}
+
+// settle discards the output until the device stops sending
+func (c *SSHConnection) settle() {
 	for {
-		n, err := r.Read(buf)
-		if err != nil {
-			ch <- result{output: "", err: err}
+		select {
+		case _, ok := <-c.chunks:
+			if !ok {
+				return
+			}
+		case <-time.After(settleTime):
+			return
 		}
-		loadStr += string(buf[:n])
-		if strings.Contains(loadStr, cmd) && re.MatchString(loadStr) {
-			break
+	}
+}
+
+func (c *SSHConnection) drain() {
+	for {
+		select {
+		case _, ok := <-c.chunks:
+			if !ok {
+				return
+			}
+		default:
+			return
 		}
 	}
-	loadStr = strings.Replace(loadStr, "\r", "", -1)
-	ch <- result{output: loadStr, err: nil}
-}
\ No newline at end of file
+}
+
+// readUntil reads until the last line of the output matches the prompt, answering pagers on the way
+func (c *SSHConnection) readUntil(prompt *regexp.Regexp) (string, error) {
+	timeout := time.After(c.clientConfig.Timeout)
+	out := ""
+	for {
+		select {
+		case chunk, ok := <-c.chunks:
+			if !ok {
+				return "", c.readErr
+			}
+			out += chunk
+			last := clean(lastLine(out))
+			if pagerRegexp.MatchString(last) {
+				out = out[:strings.LastIndexAny(out, "\r\n")+1]
+				io.WriteString(c.stdin, " ")
+				continue
+			}
+			if prompt.MatchString(last) {
+				return out, nil
+			}
+		case <-timeout:
+			return "", errTimeout
+		}
+	}
+}
+
+func (c *SSHConnection) stripEchoAndPrompt(cmd, out string) string {
+	lines := strings.Split(out, "\n")
+	if len(lines) > 0 && c.prompt.MatchString(strings.TrimRight(lines[len(lines)-1], " ")) {
+		lines = lines[:len(lines)-1]
+	}
+	if cmd != "" {
+		// the echo usually is the first line, but may be preceded by the remains of the previous prompt
+		for i := 0; i < len(lines) && i < 3; i++ {
+			if strings.Contains(lines[i], cmd) {
+				lines = lines[i+1:]
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clean removes carriage returns and the terminal control sequences of pagers
+func clean(out string) string {
+	out = terminalRegexp.ReplaceAllString(out, "")
+	return strings.Replace(out, "\r", "", -1)
+}
+
+// lastLine returns the line being printed, which is empty if the output ends with a line break
+func lastLine(out string) string {
+	if i := strings.LastIndexAny(out, "\r\n"); i >= 0 {
+		return out[i+1:]
+	}
+	return out
+}
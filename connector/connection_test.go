@@ -0,0 +1,75 @@
+package connector
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// pagedDevice prints the pages of an output one after another, waiting for a key press between them
+func pagedDevice(t *testing.T, pages []string) *SSHConnection {
+	stdinReader, stdinWriter := io.Pipe()
+	t.Cleanup(func() { stdinWriter.Close() })
+
+	c := &SSHConnection{
+		stdin:        stdinWriter,
+		clientConfig: &ssh.ClientConfig{Timeout: time.Second},
+		chunks:       make(chan string, len(pages)),
+		done:         make(chan struct{}),
+	}
+
+	go func() {
+		key := make([]byte, 1)
+		for i, page := range pages {
+			if i > 0 {
+				if _, err := stdinReader.Read(key); err != nil || key[0] != ' ' {
+					return
+				}
+			}
+			c.chunks <- page
+		}
+	}()
+	return c
+}
+
+func TestReadUntilAnswersAireOSPager(t *testing.T) {
+	c := pagedDevice(t, []string{
+		"show sysinfo\r\n\r\nManufacturer's Name.............................. Cisco Systems Inc.\r\n" +
+			"Product Name..................................... Cisco Controller\r\n--More-- or (q)uit",
+		"\r\nProduct Version.................................. 8.10.151.0\r\n--More-- or (q)uit",
+		"\r\nSystem Name...................................... wlc01\r\n\r\n(Cisco Controller) >",
+	})
+
+	out, err := c.readUntil(regexp.MustCompile(`\(.+\)\s?>\s?$`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out = clean(out)
+	for _, expected := range []string{"Cisco Controller", "8.10.151.0", "wlc01"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got %q", expected, out)
+		}
+	}
+	if strings.Contains(out, "--More--") {
+		t.Errorf("expected the pager prompts to be removed, got %q", out)
+	}
+}
+
+func TestReadUntilAnswersIOSPager(t *testing.T) {
+	c := pagedDevice(t, []string{
+		"show inventory\r\nNAME: \"1\", DESCR: \"WS-C3850-48P\"\r\n --More-- ",
+		"\x08\x08\x08\x08\x08\x08\x08\x08\x08\x08        \x08\x08\x08\x08\x08\x08\x08\x08\x08\x08NAME: \"2\", DESCR: \"WS-C3850-24T\"\r\nswitch#",
+	})
+
+	out, err := c.readUntil(regexp.MustCompile(`.+#\s?$`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out = clean(out); !strings.Contains(out, `NAME: "2"`) {
+		t.Errorf("expected the second page, got %q", out)
+	}
+}
@@ -2,27 +2,43 @@ package connector
 
 import (
 	"io"
+	"strings"
+	"sync"
 
 	"github.com/moeinshahcheraghi/cisco_exporter/config"
+	"github.com/moeinshahcheraghi/cisco_exporter/secret"
+	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
 )
 
 type Device struct {
 	Host         string
 	Port         string
-	Auth         AuthMethod
+	Credentials  []*Credentials
 	ClientConfig ssh.ClientConfig
 	DeviceConfig *config.DeviceConfig
 }
 
+// Credentials is a way to log in to the device, the credentials of a device are tried in order until one is accepted
+type Credentials struct {
+	// Profile is the name of the credential profile, empty for credentials configured on the device or globally
+	Profile string
+	Auth    AuthMethod
+	// Password is sent to devices asking for the credentials again after the SSH login (AireOS)
+	Password string
+	// EnablePassword replaces the enable password of the device if set
+	EnablePassword string
+}
+
 // AuthMethod is the method to use to authenticate agaist the device
-type AuthMethod func(*ssh.ClientConfig)
+type AuthMethod func(*ssh.ClientConfig) error
 
 // AuthByPassword uses password authentication
 func AuthByPassword(username, password string) AuthMethod {
-	return func(cfg *ssh.ClientConfig) {
+	return func(cfg *ssh.ClientConfig) error {
 		cfg.User = username
 		cfg.Auth = append(cfg.Auth, ssh.Password(password))
+		return nil
 	}
 }
 
@@ -32,12 +48,99 @@ func AuthByKey(username string, key io.Reader) (AuthMethod, error) {
 	if err != nil {
 		return nil, err
 	}
-	return func(cfg *ssh.ClientConfig) {
+	return func(cfg *ssh.ClientConfig) error {
 		cfg.User = username
 		cfg.Auth = append(cfg.Auth, pk)
+		return nil
 	}, nil
 }
 
+// AuthByCredentials uses public key authentication if a key file is given, password authentication otherwise.
+// Username, password and key file may be references to secrets (e.g. 'env:NAME'), a key file reference
+// resolving to the key itself. The secrets are resolved on every connection to pick up rotated credentials.
+func AuthByCredentials(username, password, keyFile string) (AuthMethod, error) {
+	auth := func(cfg *ssh.ClientConfig) error {
+		user, err := secret.Resolve(username)
+		if err != nil {
+			return err
+		}
+
+		if keyFile != "" {
+			key, err := resolveKey(keyFile)
+			if err != nil {
+				return err
+			}
+			a, err := AuthByKey(user, strings.NewReader(key))
+			if err != nil {
+				return errors.Wrap(err, "could not load ssh private key file")
+			}
+			return a(cfg)
+		}
+
+		pass, err := secret.Resolve(password)
+		if err != nil {
+			return err
+		}
+		return AuthByPassword(user, pass)(cfg)
+	}
+
+	// fail at startup on missing files and unresolvable references
+	if err := auth(&ssh.ClientConfig{}); err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// resolveKey returns the private key a key file or a reference to a secret points to
+func resolveKey(keyFile string) (string, error) {
+	if secret.IsRef(keyFile) {
+		return secret.Resolve(keyFile)
+	}
+	key, err := secret.Resolve("file:" + keyFile)
+	if err != nil {
+		return "", errors.Wrap(err, "could not open ssh key file")
+	}
+	return key, nil
+}
+
 func (d *Device) String() string {
 	return d.Host
 }
+
+// acceptedProfiles remembers the credential profile each device accepted, it is tried first on the next connection
+// so devices migrated to another account do not log a failed login on every scrape
+var acceptedProfiles = &profileCache{profiles: make(map[string]string)}
+
+type profileCache struct {
+	mu       sync.Mutex
+	profiles map[string]string
+}
+
+// order returns the credentials with the profile the host accepted last moved to the front
+func (p *profileCache) order(host string, credentials []*Credentials) []*Credentials {
+	p.mu.Lock()
+	profile, found := p.profiles[host]
+	p.mu.Unlock()
+	if !found {
+		return credentials
+	}
+
+	ordered := make([]*Credentials, 0, len(credentials))
+	for _, c := range credentials {
+		if c.Profile == profile {
+			ordered = append(ordered, c)
+		}
+	}
+	for _, c := range credentials {
+		if c.Profile != profile {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered
+}
+
+func (p *profileCache) set(host string, profile string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.profiles[host] = profile
+}
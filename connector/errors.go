@@ -0,0 +1,74 @@
+package connector
+
+import (
+	"net"
+)
+
+// Reasons a connection to a device failed, as returned by ErrorReason
+const (
+	ReasonAuthFailed             = "auth_failed"
+	ReasonEnableRejected         = "enable_rejected"
+	ReasonCredentialsUnavailable = "credentials_unavailable"
+	ReasonTimeout                = "timeout"
+	ReasonConnectFailed          = "connect_failed"
+)
+
+// authError is returned by Connect if the device rejected the credentials
+type authError struct {
+	err error
+}
+
+func (e *authError) Error() string {
+	return e.err.Error()
+}
+
+// enableError is returned if the device rejected the enable password
+type enableError struct {
+	reason string
+}
+
+func (e *enableError) Error() string {
+	return "enable failed: " + e.reason
+}
+
+// credentialsError is returned if the secrets of the credentials could not be resolved
+type credentialsError struct {
+	err error
+}
+
+func (e *credentialsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *credentialsError) Cause() error {
+	return e.err
+}
+
+// ErrorReason returns the reason an error of NewSSSHConnection was caused by, the error itself
+// contains addresses and messages of the device which are not suited to be a label value
+func ErrorReason(err error) string {
+	for err != nil {
+		switch e := err.(type) {
+		case *authError:
+			return ReasonAuthFailed
+		case *enableError:
+			return ReasonEnableRejected
+		case *credentialsError:
+			return ReasonCredentialsUnavailable
+		case net.Error:
+			if e.Timeout() {
+				return ReasonTimeout
+			}
+		}
+		if err == errTimeout {
+			return ReasonTimeout
+		}
+
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return ReasonConnectFailed
+}
@@ -0,0 +1,196 @@
+package connector
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/config"
+	"github.com/moeinshahcheraghi/cisco_exporter/secret"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// jumpHosts holds the connections to bastions, shared by all devices tunnelled through the same chain
+var jumpHosts = &jumpHostPool{hops: make(map[string]*jumpHostHop)}
+
+type jumpHostPool struct {
+	mu   sync.Mutex
+	hops map[string]*jumpHostHop
+}
+
+// jumpHostHop is the connection to a hop of a chain, locked while it is checked or connected
+// so devices behind other bastions are not kept waiting
+type jumpHostHop struct {
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// dial returns a connection to the last jump host of the chain, connecting the hops not connected yet.
+// The first hop is reached through the proxy if one is given.
+func (p *jumpHostPool) dial(chain []*config.JumpHostConfig, proxy string, defaultUsername string, timeout time.Duration) (*ssh.Client, error) {
+	var client *ssh.Client
+	keys := []string{proxy}
+	for _, j := range chain {
+		cfg, err := jumpHostClientConfig(j, defaultUsername, timeout)
+		if err != nil {
+			return nil, err
+		}
+		addr := hostWithPort(j.Host)
+		keys = append(keys, cfg.User+"@"+addr)
+
+		client, err = p.hop(strings.Join(keys, ">")).connect(client, proxy, addr, cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not connect to jump host %s", j.Host)
+		}
+	}
+	return client, nil
+}
+
+func (p *jumpHostPool) hop(key string) *jumpHostHop {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h, found := p.hops[key]
+	if !found {
+		h = &jumpHostHop{}
+		p.hops[key] = h
+	}
+	return h
+}
+
+// connect returns the connection to the hop if it still responds, otherwise it connects again
+func (h *jumpHostHop) connect(jump *ssh.Client, proxy string, addr string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.client != nil {
+		if keepalive(h.client, cfg.Timeout) {
+			return h.client, nil
+		}
+		h.client.Close()
+		h.client = nil
+	}
+
+	c, err := dialThrough(jump, proxy, addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	h.client = c
+	return c, nil
+}
+
+// keepalive reports whether the bastion answers a keepalive request within the timeout
+func keepalive(client *ssh.Client, timeout time.Duration) bool {
+	ch := make(chan error, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		ch <- err
+	}()
+
+	select {
+	case err := <-ch:
+		return err == nil
+	case <-time.After(timeout):
+		// closing the connection makes the pending request return
+		client.Close()
+		return false
+	}
+}
+
+// dialThrough opens an SSH connection to addr, tunnelled through the jump host if there is one,
+// otherwise through the proxy if there is one
+func dialThrough(jump *ssh.Client, proxy string, addr string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	var conn net.Conn
+	var err error
+	switch {
+	case jump != nil:
+		conn, err = dialJump(jump, addr, cfg.Timeout)
+	case proxy != "":
+		conn, err = dialProxy(proxy, addr, cfg.Timeout)
+	default:
+		conn, err = net.DialTimeout("tcp", addr, cfg.Timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// dialJump opens a TCP connection from the jump host, which has no timeout of its own
+func dialJump(jump *ssh.Client, addr string, timeout time.Duration) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		conn, err := jump.Dial("tcp", addr)
+		ch <- result{conn, err}
+	}()
+
+	select {
+	case res := <-ch:
+		return res.conn, res.err
+	case <-time.After(timeout):
+		// the connection may still be opened after giving up on it
+		go func() {
+			if res := <-ch; res.conn != nil {
+				res.conn.Close()
+			}
+		}()
+		return nil, errors.Wrap(errTimeout, "could not connect to "+addr)
+	}
+}
+
+func jumpHostClientConfig(j *config.JumpHostConfig, defaultUsername string, timeout time.Duration) (*ssh.ClientConfig, error) {
+	cfg := &ssh.ClientConfig{
+		User:            defaultUsername,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         timeout,
+	}
+	if j.Username != "" {
+		user, err := secret.Resolve(j.Username)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not resolve username of jump host")
+		}
+		cfg.User = user
+	}
+
+	if j.KeyFile != "" {
+		key, err := resolveKey(j.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not resolve ssh key of jump host")
+		}
+		auth, err := AuthByKey(cfg.User, strings.NewReader(key))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load ssh private key file of jump host")
+		}
+		auth(cfg)
+	}
+	if j.Password != "" {
+		password, err := secret.Resolve(j.Password)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not resolve password of jump host")
+		}
+		AuthByPassword(cfg.User, password)(cfg)
+	}
+	if len(cfg.Auth) == 0 {
+		return nil, errors.New("no valid authentication method available for jump host " + j.Host)
+	}
+	return cfg, nil
+}
+
+func hostWithPort(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, "22")
+}
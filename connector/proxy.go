@@ -0,0 +1,227 @@
+package connector
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// socks5Errors are the reply codes of RFC 1928
+var socks5Errors = map[byte]string{
+	1: "general SOCKS server failure",
+	2: "connection not allowed by ruleset",
+	3: "network unreachable",
+	4: "host unreachable",
+	5: "connection refused",
+	6: "TTL expired",
+	7: "command not supported",
+	8: "address type not supported",
+}
+
+// CheckProxyURL validates a proxy URL as accepted by the proxy options
+func CheckProxyURL(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return errors.Wrap(err, "invalid proxy URL")
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h", "http":
+	default:
+		return errors.New("unsupported proxy scheme " + u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.New("proxy URL without host")
+	}
+	return nil
+}
+
+// dialProxy connects to addr through a proxy given as URL, 'socks5://', 'socks5h://' (resolved by the proxy)
+// or 'http://' (HTTP CONNECT), with optional 'user:password@' credentials
+func dialProxy(proxy string, addr string, timeout time.Duration) (net.Conn, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proxy URL")
+	}
+
+	conn, err := net.DialTimeout("tcp", proxyAddr(u), timeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to proxy")
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		err = socks5Connect(conn, u, addr)
+	case "http":
+		conn, err = httpConnect(conn, u, addr)
+	default:
+		err = errors.New("unsupported proxy scheme " + u.Scheme)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// proxyAddr returns the address of the proxy, defaulting to port 1080 for SOCKS and 8080 for HTTP
+func proxyAddr(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "http" {
+		return net.JoinHostPort(u.Hostname(), "8080")
+	}
+	return net.JoinHostPort(u.Hostname(), "1080")
+}
+
+func socks5Connect(conn net.Conn, u *url.URL, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return errors.New("invalid port " + portStr)
+	}
+
+	// greeting: no authentication, or username/password if credentials are given
+	methods := []byte{0x00}
+	if u.User != nil {
+		methods = append(methods, 0x02)
+	}
+	if _, err := conn.Write(append([]byte{0x05, byte(len(methods))}, methods...)); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return errors.Wrap(err, "SOCKS5 greeting failed")
+	}
+	if reply[0] != 0x05 {
+		return errors.New("proxy is not a SOCKS5 server")
+	}
+
+	switch reply[1] {
+	case 0x00:
+	case 0x02:
+		if u.User == nil {
+			return errors.New("SOCKS5 proxy requires authentication")
+		}
+		password, _ := u.User.Password()
+		req := []byte{0x01, byte(len(u.User.Username()))}
+		req = append(req, u.User.Username()...)
+		req = append(req, byte(len(password)))
+		req = append(req, password...)
+		if _, err := conn.Write(req); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return errors.Wrap(err, "SOCKS5 authentication failed")
+		}
+		if reply[1] != 0x00 {
+			return errors.New("SOCKS5 authentication rejected")
+		}
+	default:
+		return errors.New("SOCKS5 proxy accepts no supported authentication method")
+	}
+
+	// 'socks5' resolves the name locally, 'socks5h' leaves it to the proxy
+	if ip := net.ParseIP(host); ip == nil && u.Scheme == "socks5" {
+		ips, err := net.LookupIP(host)
+		if err != nil {
+			return err
+		}
+		host = ips[0].String()
+	}
+	req := []byte{0x05, 0x01, 0x00}
+	if ip := net.ParseIP(host); ip == nil {
+		req = append(req, 0x03, byte(len(host)))
+		req = append(req, host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		req = append(req, 0x01)
+		req = append(req, ip4...)
+	} else {
+		req = append(req, 0x04)
+		req = append(req, ip.To16()...)
+	}
+	req = append(req, 0, 0)
+	binary.BigEndian.PutUint16(req[len(req)-2:], uint16(port))
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return errors.Wrap(err, "SOCKS5 connect failed")
+	}
+	if header[1] != 0x00 {
+		msg, found := socks5Errors[header[1]]
+		if !found {
+			msg = fmt.Sprintf("unknown error %d", header[1])
+		}
+		return errors.New("SOCKS5 connect to " + addr + " failed: " + msg)
+	}
+
+	// skip the bound address and port
+	var skip int
+	switch header[3] {
+	case 0x01:
+		skip = net.IPv4len + 2
+	case 0x04:
+		skip = net.IPv6len + 2
+	case 0x03:
+		l := make([]byte, 1)
+		if _, err := io.ReadFull(conn, l); err != nil {
+			return err
+		}
+		skip = int(l[0]) + 2
+	default:
+		return errors.New("SOCKS5 reply has unknown address type")
+	}
+	_, err = io.ReadFull(conn, make([]byte, skip))
+	return err
+}
+
+func httpConnect(conn net.Conn, u *url.URL, addr string) (net.Conn, error) {
+	req := "CONNECT " + addr + " HTTP/1.1\r\nHost: " + addr + "\r\n"
+	if u.User != nil {
+		password, _ := u.User.Password()
+		req += "Proxy-Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(u.User.Username()+":"+password)) + "\r\n"
+	}
+	if _, err := io.WriteString(conn, req+"\r\n"); err != nil {
+		return conn, err
+	}
+
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return conn, errors.Wrap(err, "HTTP CONNECT failed")
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return conn, errors.New("HTTP CONNECT to " + addr + " failed: " + resp.Status)
+	}
+
+	// the SSH server talks first, its banner may already be buffered
+	return &bufferedConn{Conn: conn, r: r}, nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
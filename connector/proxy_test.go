@@ -0,0 +1,276 @@
+package connector
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const banner = "SSH-2.0-test\r\n"
+
+// listen serves each connection accepted by a new listener with handle
+func listen(t *testing.T, handle func(net.Conn)) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+// sshServer greets like an SSH server
+func sshServer(t *testing.T) string {
+	return listen(t, func(conn net.Conn) {
+		io.WriteString(conn, banner)
+		io.Copy(io.Discard, conn)
+	})
+}
+
+type socks5Request struct {
+	addrType byte
+	host     string
+}
+
+// socks5Server is a SOCKS5 proxy requiring the credentials if user is not empty,
+// the requests it received are sent to the channel
+func socks5Server(t *testing.T, user, password string, requests chan<- socks5Request) string {
+	return listen(t, func(conn net.Conn) {
+		b := make([]byte, 2)
+		if _, err := io.ReadFull(conn, b); err != nil {
+			return
+		}
+		methods := make([]byte, b[1])
+		io.ReadFull(conn, methods)
+
+		if user == "" {
+			conn.Write([]byte{0x05, 0x00})
+		} else if !strings.ContainsRune(string(methods), 0x02) {
+			conn.Write([]byte{0x05, 0xff})
+			return
+		} else {
+			conn.Write([]byte{0x05, 0x02})
+			io.ReadFull(conn, b)
+			u := make([]byte, b[1])
+			io.ReadFull(conn, u)
+			io.ReadFull(conn, b[:1])
+			p := make([]byte, b[0])
+			io.ReadFull(conn, p)
+			if string(u) != user || string(p) != password {
+				conn.Write([]byte{0x01, 0x01})
+				return
+			}
+			conn.Write([]byte{0x01, 0x00})
+		}
+
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		var host string
+		switch header[3] {
+		case 0x01:
+			ip := make([]byte, net.IPv4len)
+			io.ReadFull(conn, ip)
+			host = net.IP(ip).String()
+		case 0x04:
+			ip := make([]byte, net.IPv6len)
+			io.ReadFull(conn, ip)
+			host = net.IP(ip).String()
+		case 0x03:
+			io.ReadFull(conn, b[:1])
+			name := make([]byte, b[0])
+			io.ReadFull(conn, name)
+			host = string(name)
+		}
+		port := make([]byte, 2)
+		io.ReadFull(conn, port)
+		requests <- socks5Request{addrType: header[3], host: host}
+
+		target, err := net.Dial("tcp", net.JoinHostPort(host, fmt.Sprint(binary.BigEndian.Uint16(port))))
+		if err != nil {
+			conn.Write([]byte{0x05, 0x05, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		defer target.Close()
+		conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 127, 0, 0, 1, 0, 0})
+		go io.Copy(target, conn)
+		io.Copy(conn, target)
+	})
+}
+
+// httpProxy answers CONNECT requests with the status, sending the banner in the same write as the response
+func httpProxy(t *testing.T, status int, auth chan<- string) string {
+	return listen(t, func(conn net.Conn) {
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		auth <- req.Header.Get("Proxy-Authorization")
+		if status != http.StatusOK {
+			fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\nContent-Length: 0\r\n\r\n", status, http.StatusText(status))
+			return
+		}
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"+banner)
+		io.Copy(io.Discard, conn)
+	})
+}
+
+func readBanner(t *testing.T, conn net.Conn) {
+	t.Helper()
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != banner {
+		t.Fatalf("expected banner %q, got %q", banner, line)
+	}
+}
+
+func TestDialProxySOCKS5(t *testing.T) {
+	target := sshServer(t)
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		proxyURL string
+		err      string
+	}{
+		{name: "no authentication", proxyURL: "socks5://%s"},
+		{name: "authentication", user: "u", password: "p", proxyURL: "socks5://u:p@%s"},
+		{name: "wrong password", user: "u", password: "p", proxyURL: "socks5://u:x@%s", err: "SOCKS5 authentication rejected"},
+		{name: "missing credentials", user: "u", password: "p", proxyURL: "socks5://%s", err: "SOCKS5 proxy accepts no supported authentication method"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := make(chan socks5Request, 1)
+			proxy := socks5Server(t, test.user, test.password, requests)
+
+			conn, err := dialProxy(fmt.Sprintf(test.proxyURL, proxy), target, time.Second)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("expected error %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			readBanner(t, conn)
+		})
+	}
+}
+
+func TestDialProxySOCKS5NameResolution(t *testing.T) {
+	_, port, _ := net.SplitHostPort(sshServer(t))
+	target := net.JoinHostPort("localhost", port)
+
+	requests := make(chan socks5Request, 1)
+	proxy := socks5Server(t, "", "", requests)
+
+	conn, err := dialProxy("socks5://"+proxy, target, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if r := <-requests; r.addrType == 0x03 {
+		t.Fatalf("socks5 must resolve the name locally, proxy got name %q", r.host)
+	}
+
+	conn, err = dialProxy("socks5h://"+proxy, target, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if r := <-requests; r.addrType != 0x03 || r.host != "localhost" {
+		t.Fatalf("socks5h must leave the name to the proxy, proxy got address type %d host %q", r.addrType, r.host)
+	}
+}
+
+func TestDialProxySOCKS5ConnectFailure(t *testing.T) {
+	requests := make(chan socks5Request, 1)
+	proxy := socks5Server(t, "", "", requests)
+
+	// nothing listens on the port of the closed listener
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	target := l.Addr().String()
+	l.Close()
+
+	_, err := dialProxy("socks5://"+proxy, target, time.Second)
+	if err == nil || !strings.HasSuffix(err.Error(), "connection refused") {
+		t.Fatalf("expected connection refused, got %v", err)
+	}
+}
+
+func TestDialProxyHTTP(t *testing.T) {
+	auth := make(chan string, 1)
+	proxy := httpProxy(t, http.StatusOK, auth)
+
+	conn, err := dialProxy("http://u:p@"+proxy, "10.0.0.1:22", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("u:p"))
+	if a := <-auth; a != expected {
+		t.Fatalf("expected Proxy-Authorization %q, got %q", expected, a)
+	}
+
+	// the banner arrives with the response and must not be lost in the buffer of the response reader
+	readBanner(t, conn)
+}
+
+func TestDialProxyHTTPAuthenticationRequired(t *testing.T) {
+	auth := make(chan string, 1)
+	proxy := httpProxy(t, http.StatusProxyAuthRequired, auth)
+
+	_, err := dialProxy("http://"+proxy, "10.0.0.1:22", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "407") {
+		t.Fatalf("expected 407 error, got %v", err)
+	}
+	if a := <-auth; a != "" {
+		t.Fatalf("expected no Proxy-Authorization, got %q", a)
+	}
+}
+
+func TestProxyAddr(t *testing.T) {
+	tests := map[string]string{
+		"socks5://proxy":        "proxy:1080",
+		"socks5h://proxy":       "proxy:1080",
+		"http://proxy":          "proxy:8080",
+		"http://u:p@proxy:3128": "proxy:3128",
+		"socks5://[::1]":        "[::1]:1080",
+	}
+	for proxy, expected := range tests {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr := proxyAddr(u); addr != expected {
+			t.Errorf("%s: expected %s, got %s", proxy, expected, addr)
+		}
+	}
+}
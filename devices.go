@@ -1,11 +1,11 @@
 package main
 
 import (
-	"os"
 	"strings"
 
 	"github.com/moeinshahcheraghi/cisco_exporter/config"
 	"github.com/moeinshahcheraghi/cisco_exporter/connector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
 	"github.com/pkg/errors"
 )
 
@@ -23,10 +23,22 @@ func devicesForConfig(cfg *config.Config) ([]*connector.Device, error) {
 }
 
 func deviceFromDeviceConfig(device *config.DeviceConfig, cfg *config.Config) (*connector.Device, error) {
-	auth, err := authForDevice(device, cfg)
+	credentials, err := authForDevice(device, cfg)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not initialize config for device %s", device.Host)
 	}
+	proxy := cfg.Proxy
+	if device.Proxy != nil {
+		proxy = *device.Proxy
+	}
+	if proxy != "" {
+		if err := connector.CheckProxyURL(proxy); err != nil {
+			return nil, errors.Wrapf(err, "could not initialize config for device %s", device.Host)
+		}
+	}
+	if device.OSType != nil && rpc.DriverForHint(*device.OSType, "") == nil {
+		return nil, errors.Errorf("unknown os_type %q for device %s", *device.OSType, device.Host)
+	}
 
 	port := "22"
 	host := device.Host
@@ -39,46 +51,88 @@ func deviceFromDeviceConfig(device *config.DeviceConfig, cfg *config.Config) (*c
 	return &connector.Device{
 		Host:         host,
 		Port:         port,
-		Auth:         auth,
+		Credentials:  credentials,
 		DeviceConfig: device,
 	}, nil
 }
 
-func authForDevice(device *config.DeviceConfig, cfg *config.Config) (connector.AuthMethod, error) {
+// authForDevice returns the credentials to try in order, which are the credential profiles listed by the device,
+// the credentials configured on the device or the credential profiles listed globally
+func authForDevice(device *config.DeviceConfig, cfg *config.Config) ([]*connector.Credentials, error) {
 	user := cfg.Username
 	if device.Username != nil {
 		user = *device.Username
 	}
 
+	if device.Credentials != nil {
+		return authForProfiles(device.Credentials, user, cfg)
+	}
+
+	if device.KeyFile == nil && device.Password == nil && len(cfg.Credentials) > 0 {
+		return authForProfiles(cfg.Credentials, user, cfg)
+	}
+
+	auth, err := authForCredentials(device, cfg, user)
+	if err != nil {
+		return nil, err
+	}
+	password := cfg.Password
+	if device.Password != nil {
+		password = *device.Password
+	}
+	return []*connector.Credentials{{Auth: auth, Password: password}}, nil
+}
+
+func authForCredentials(device *config.DeviceConfig, cfg *config.Config, user string) (connector.AuthMethod, error) {
 	if device.KeyFile != nil {
-		return authForKeyFile(user, *device.KeyFile)
+		return connector.AuthByCredentials(user, "", *device.KeyFile)
 	}
 
 	if cfg.KeyFile != "" {
-		return authForKeyFile(user, cfg.KeyFile)
+		return connector.AuthByCredentials(user, "", cfg.KeyFile)
 	}
 
 	if device.Password != nil {
-		return connector.AuthByPassword(user, *device.Password), nil
+		return connector.AuthByCredentials(user, *device.Password, "")
 	}
 
 	if cfg.Password != "" {
-		return connector.AuthByPassword(user, cfg.Password), nil
+		return connector.AuthByCredentials(user, cfg.Password, "")
 	}
 
 	return nil, errors.New("no valid authentication method available")
 }
 
-func authForKeyFile(username, keyFile string) (connector.AuthMethod, error) {
-	f, err := os.Open(keyFile)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not open ssh key file")
-	}
-	defer f.Close()
-	auth, err := connector.AuthByKey(username, f)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not load ssh private key file")
+// authForProfiles returns the credentials of the profiles, their username defaulting to the one of the device
+func authForProfiles(names []string, user string, cfg *config.Config) ([]*connector.Credentials, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no credential profile listed")
 	}
 
-	return auth, nil
+	credentials := make([]*connector.Credentials, len(names))
+	for i, name := range names {
+		p, found := cfg.Profiles[name]
+		if !found || p == nil {
+			return nil, errors.Errorf("unknown credential profile %q", name)
+		}
+		if p.KeyFile == "" && p.Password == "" {
+			return nil, errors.Errorf("credential profile %q has neither password nor key_file", name)
+		}
+
+		username := user
+		if p.Username != "" {
+			username = p.Username
+		}
+		auth, err := connector.AuthByCredentials(username, p.Password, p.KeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "credential profile %q", name)
+		}
+		credentials[i] = &connector.Credentials{
+			Profile:        name,
+			Auth:           auth,
+			Password:       p.Password,
+			EnablePassword: p.EnablePassword,
+		}
+	}
+	return credentials, nil
 }
@@ -11,14 +11,30 @@ import (
 const prefix string = "cisco_environment_"
 
 var (
-	temperaturesDesc *prometheus.Desc
-	powerSupplyDesc  *prometheus.Desc
+	temperaturesDesc  *prometheus.Desc
+	temperatureUpDesc *prometheus.Desc
+	tempThresholdDesc *prometheus.Desc
+	fanUpDesc         *prometheus.Desc
+	fanSpeedDesc      *prometheus.Desc
+	powerSupplyDesc   *prometheus.Desc
+	powerInputDesc    *prometheus.Desc
+	powerOutputDesc   *prometheus.Desc
+	powerCapacityDesc *prometheus.Desc
+	powerBudgetDesc   *prometheus.Desc
 )
 
 func init() {
 	l := []string{"target", "item"}
 	temperaturesDesc = prometheus.NewDesc(prefix+"sensor_temp", "Sensor temperatures", l, nil)
+	tempThresholdDesc = prometheus.NewDesc(prefix+"sensor_temp_threshold", "Yellow (minor) and red (major) temperature thresholds of a sensor", append(l, "level"), nil)
+	fanSpeedDesc = prometheus.NewDesc(prefix+"fan_speed_rpm", "Fan speed in revolutions per minute", l, nil)
+	powerInputDesc = prometheus.NewDesc(prefix+"power_input_watts", "Input power drawn by a power supply in watts", l, nil)
+	powerOutputDesc = prometheus.NewDesc(prefix+"power_output_watts", "Output power delivered by a power supply in watts", l, nil)
+	powerCapacityDesc = prometheus.NewDesc(prefix+"power_capacity_watts", "Capacity of a power supply in watts", l, nil)
+	powerBudgetDesc = prometheus.NewDesc(prefix+"power_budget_watts", "Power budget of the device in watts", []string{"target", "type"}, nil)
 	l = append(l, "status")
+	temperatureUpDesc = prometheus.NewDesc(prefix+"sensor_up", "Status of temperature sensors (1 OK, 0 Something is wrong)", l, nil)
+	fanUpDesc = prometheus.NewDesc(prefix+"fan_up", "Status of fans (1 OK, 0 Something is wrong)", l, nil)
 	powerSupplyDesc = prometheus.NewDesc(prefix+"power_up", "Status of power supplies (1 OK, 0 Something is wrong)", l, nil)
 }
 
@@ -37,16 +53,36 @@ func (*environmentCollector) Name() string {
 // Describe describes the metrics
 func (*environmentCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- temperaturesDesc
+	ch <- temperatureUpDesc
+	ch <- tempThresholdDesc
+	ch <- fanUpDesc
+	ch <- fanSpeedDesc
 	ch <- powerSupplyDesc
+	ch <- powerInputDesc
+	ch <- powerOutputDesc
+	ch <- powerCapacityDesc
+	ch <- powerBudgetDesc
 }
 
 // Collect collects metrics from Cisco
 func (c *environmentCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
-	out, err := client.RunCommand("show environment all")
+	cmd, extraCmd := "show environment all", "show power"
+	if client.OSType == rpc.NXOS {
+		cmd, extraCmd = "show environment", "show environment fan detail"
+	}
+	out, err := client.RunCommand(cmd)
 	if err != nil {
 		return err
 	}
-	items, err := Parse(client.OSType, out)
+	extra, err := client.RunCommand(extraCmd)
+	if err != nil {
+		if client.Debug {
+			log.Printf("Command '%s' on %s: %s\n", extraCmd, labelValues[0], err.Error())
+		}
+	} else {
+		out += "\n" + extra
+	}
+	env, err := Parse(client.OSType, out)
 	if err != nil {
 		if client.Debug {
 			log.Printf("Parse environment for %s: %s\n", labelValues[0], err.Error())
@@ -54,19 +90,53 @@ func (c *environmentCollector) Collect(client *rpc.Client, ch chan<- prometheus.
 		return nil
 	}
 
-	for _, item := range items {
-		l := append(labelValues, item.Name)
-		if item.IsTemp {
-			ch <- prometheus.MustNewConstMetric(temperaturesDesc, prometheus.GaugeValue, float64(item.Temperature), l...)
-		} else {
-			val := 0
-			if item.OK {
-				val = 1
-			}
-			l = append(l, item.Status)
-			ch <- prometheus.MustNewConstMetric(powerSupplyDesc, prometheus.GaugeValue, float64(val), l...)
+	for _, t := range env.Temperatures {
+		l := append(labelValues, t.Name)
+		ch <- prometheus.MustNewConstMetric(temperaturesDesc, prometheus.GaugeValue, t.Temperature, l...)
+		if t.Status != "" {
+			ch <- prometheus.MustNewConstMetric(temperatureUpDesc, prometheus.GaugeValue, boolValue(statusOK(t.Status)), append(l, t.Status)...)
+		}
+		if t.HasYellow {
+			ch <- prometheus.MustNewConstMetric(tempThresholdDesc, prometheus.GaugeValue, t.Yellow, append(l, "yellow")...)
+		}
+		if t.HasRed {
+			ch <- prometheus.MustNewConstMetric(tempThresholdDesc, prometheus.GaugeValue, t.Red, append(l, "red")...)
+		}
+	}
+	for _, f := range env.Fans {
+		l := append(labelValues, f.Name)
+		if f.Status != "" {
+			ch <- prometheus.MustNewConstMetric(fanUpDesc, prometheus.GaugeValue, boolValue(statusOK(f.Status)), append(l, f.Status)...)
+		}
+		if f.HasRPM {
+			ch <- prometheus.MustNewConstMetric(fanSpeedDesc, prometheus.GaugeValue, f.RPM, l...)
 		}
 	}
+	for _, p := range env.PowerSupplies {
+		l := append(labelValues, p.Name)
+		if p.Status != "" {
+			ch <- prometheus.MustNewConstMetric(powerSupplyDesc, prometheus.GaugeValue, boolValue(statusOK(p.Status)), append(l, p.Status)...)
+		}
+		if p.HasInput {
+			ch <- prometheus.MustNewConstMetric(powerInputDesc, prometheus.GaugeValue, p.InputWatts, l...)
+		}
+		if p.HasOutput {
+			ch <- prometheus.MustNewConstMetric(powerOutputDesc, prometheus.GaugeValue, p.OutputWatts, l...)
+		}
+		if p.HasCapacity {
+			ch <- prometheus.MustNewConstMetric(powerCapacityDesc, prometheus.GaugeValue, p.Capacity, l...)
+		}
+	}
+	for t, v := range env.Budget {
+		ch <- prometheus.MustNewConstMetric(powerBudgetDesc, prometheus.GaugeValue, v, append(labelValues, t)...)
+	}
 
 	return nil
 }
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -1,9 +1,40 @@
 package environment
 
-type EnvironmentItem struct {
+// Environment holds the sensors, fans, power supplies and power budget of a device
+type Environment struct {
+	Temperatures  []TemperatureSensor
+	Fans          []Fan
+	PowerSupplies []PowerSupply
+	Budget        map[string]float64
+}
+
+// TemperatureSensor is a temperature reading with its yellow (minor) and red (major) thresholds
+type TemperatureSensor struct {
 	Name        string
 	Status      string
-	OK          bool
-	IsTemp      bool
 	Temperature float64
+	Yellow      float64
+	Red         float64
+	HasYellow   bool
+	HasRed      bool
+}
+
+// Fan is the state and (where reported) the speed of a fan
+type Fan struct {
+	Name   string
+	Status string
+	RPM    float64
+	HasRPM bool
+}
+
+// PowerSupply is the state and (where reported) the input/output power and capacity of a power supply
+type PowerSupply struct {
+	Name        string
+	Status      string
+	InputWatts  float64
+	OutputWatts float64
+	Capacity    float64
+	HasInput    bool
+	HasOutput   bool
+	HasCapacity bool
 }
@@ -2,73 +2,350 @@ package environment
 
 import (
 	"errors"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
 	"github.com/moeinshahcheraghi/cisco_exporter/util"
 )
 
-// ✅ Exported function for parsing
-func Parse(ostype string, output string) ([]EnvironmentItem, error) {
+const (
+	tableNone = iota
+	tableStackPower
+	tableStackFan
+	tableSensors
+	tableChassisPower
+	tablePowerSummary
+	tableNXPower
+	tableNXFan
+	tableNXFanDetail
+	tableNXTemperature
+)
+
+var (
+	tempValueRegexp     = regexp.MustCompile(`^(.*?)\s*Temperature Value\s*:\s*(-?\d+(?:\.\d+)?)`)
+	tempStateRegexp     = regexp.MustCompile(`^Temperature State\s*:\s*(\S+)`)
+	tempThresholdRegexp = regexp.MustCompile(`^(Yellow|Red) Threshold\s*:\s*(-?\d+(?:\.\d+)?)`)
+	fanStatusRegexp     = regexp.MustCompile(`^(.*\bFAN\b.*?) is (.+)$`)
+	powerStatusRegexp   = regexp.MustCompile(`^((?:Switch \d+ )?(?:POWER|RPS)) is (.+)$`)
+	stackPowerRegexp    = regexp.MustCompile(`^\d+[A-D]$`)
+	sensorRegexp        = regexp.MustCompile(`^(.+?)\s+(\S+)\s+(\S+)\s+(-?\d+(?:\.\d+)?)\s*(Celsius|RPM|W|V AC|V DC|mV|V|mA|A)\b\s*(.*)$`)
+	sensorLimitsRegexp  = regexp.MustCompile(`^\(\s*(-?\d+)\s*,\s*(-?\d+)`)
+	chassisPowerRegexp  = regexp.MustCompile(`^(PS\d+)\s+\S+\s+\S+\s+(\d+)\s*W\s+(\S+)`)
+	wattsRegexp         = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*W\b`)
+	totalMaxRegexp      = regexp.MustCompile(`Total Maximum Available\s*=\s*(\d+)`)
+	nxosBudgetRegexp    = regexp.MustCompile(`^Total Power (Capacity|Output|Allocated|Available)\b.*?\s(\d+(?:\.\d+)?)\s*W`)
+	nxosFanNameRegexp   = regexp.MustCompile(`^(Fan\S*\d\S*)\s`)
+)
+
+// nxosBudgetTypes maps the 'Total Power ...' lines of NX-OS to budget types
+var nxosBudgetTypes = map[string]string{
+	"Capacity":  "capacity",
+	"Output":    "used",
+	"Allocated": "allocated",
+	"Available": "available",
+}
+
+type parser struct {
+	env   Environment
+	temps map[string]int
+	fans  map[string]int
+	psus  map[string]int
+}
+
+// Parse parses the output of 'show environment' ('show environment all' and 'show power' on IOS and IOS XE,
+// 'show environment' and 'show environment fan detail' on NX-OS) and returns temperatures with thresholds,
+// fan states and speeds, power supply states and watts and the power budget
+func Parse(ostype string, output string) (Environment, error) {
 	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
-		return nil, errors.New("'show environment' is not implemented for " + ostype)
+		return Environment{}, errors.New("'show environment' is not implemented for " + ostype)
 	}
 
-	items := []EnvironmentItem{}
-	lines := strings.Split(output, "\n")
-	var currentSwitch string
+	p := &parser{
+		env:   Environment{Budget: make(map[string]float64)},
+		temps: make(map[string]int),
+		fans:  make(map[string]int),
+		psus:  make(map[string]int),
+	}
+	table := tableNone
+	slotFirst := false
+	nxosInput := false
+	currentSwitch := ""
+	lastTemp := ""
 
+	lines := strings.Split(output, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-
-		// Detect Switch N:
-		if strings.HasPrefix(line, "Switch ") && strings.Contains(line, ":") {
-			currentSwitch = strings.Split(line, ":")[0]
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			table = tableNone
+			continue
+		}
+		if strings.HasPrefix(line, "---") || fields[0] == "show" || strings.HasSuffix(line, "#") {
 			continue
 		}
 
-		// Inlet / Hotspot temperatures
-		if strings.HasPrefix(line, "Inlet Temperature Value:") || strings.HasPrefix(line, "Hotspot Temperature Value:") {
-			parts := strings.Split(line, ":")
-			if len(parts) == 2 {
-				name := strings.TrimSpace(parts[0])
-				tempStr := strings.TrimSpace(strings.Replace(parts[1], "Degree Celsius", "", 1))
-				temp := util.Str2float64(tempStr)
-
-				items = append(items, EnvironmentItem{
-					Name:        currentSwitch + " " + name,
-					IsTemp:      true,
-					Temperature: temp,
-				})
+		switch {
+		case fields[0] == "SW" && len(fields) > 1 && fields[1] == "PID":
+			table = tableStackPower
+			continue
+		case fields[0] == "Switch" && len(fields) > 2 && fields[1] == "FAN" && fields[2] == "Speed":
+			table = tableStackFan
+			continue
+		case (fields[0] == "Slot" || fields[0] == "Sensor") && strings.Contains(line, "State") && strings.Contains(line, "Reading"):
+			table = tableSensors
+			slotFirst = fields[0] == "Slot"
+			continue
+		case fields[0] == "Supply" && strings.Contains(line, "Model"):
+			if strings.Contains(line, "Output") {
+				table = tableNXPower
+				nxosInput = strings.Contains(line, "Input")
+			} else {
+				table = tableChassisPower
 			}
+			continue
+		case fields[0] == "Power" && len(fields) > 1 && fields[1] == "Summary":
+			table = tablePowerSummary
+			continue
+		case fields[0] == "Fan" && strings.Contains(line, "Status"):
+			table = tableNXFan
+			continue
+		case fields[0] == "Fan" && strings.Contains(line, "Speed(RPM)"):
+			table = tableNXFanDetail
+			continue
+		case fields[0] == "Module" && strings.Contains(line, "CurTemp"):
+			table = tableNXTemperature
+			continue
 		}
 
-		// FAN or PSU status lines
-		if strings.Contains(line, "FAN") && strings.Contains(line, "is OK") {
-			items = append(items, EnvironmentItem{
-				Name:   line,
-				IsTemp: false,
-				OK:     true,
-				Status: "OK",
-			})
+		if matches := nxosBudgetRegexp.FindStringSubmatch(line); matches != nil {
+			p.env.Budget[nxosBudgetTypes[matches[1]]] = util.Str2float64(matches[2])
+			continue
 		}
 
-		// PSU table at the bottom
-		if strings.HasPrefix(line, "1A") || strings.HasPrefix(line, "1B") || strings.HasPrefix(line, "2A") || strings.HasPrefix(line, "2B") {
-			fields := strings.Fields(line)
-			if len(fields) >= 4 {
-				name := fields[0] + " " + fields[1]
-				status := fields[3]
-				ok := status == "OK"
-				items = append(items, EnvironmentItem{
-					Name:   name,
-					IsTemp: false,
-					OK:     ok,
-					Status: status,
-				})
+		switch table {
+		case tableStackPower:
+			p.parseStackPower(fields)
+			continue
+		case tableStackFan:
+			if len(fields) >= 4 && allNumbers(fields[:3]) {
+				f := p.fan("Switch " + fields[0] + " FAN " + fields[1])
+				f.RPM, f.HasRPM = util.Str2float64(fields[2]), true
+				f.Status = fields[3]
+			}
+			continue
+		case tableSensors:
+			p.parseSensor(line, slotFirst)
+			continue
+		case tableChassisPower:
+			if matches := chassisPowerRegexp.FindStringSubmatch(line); matches != nil {
+				s := p.psu(matches[1])
+				s.Capacity, s.HasCapacity = util.Str2float64(matches[2]), true
+				s.Status = matches[3]
+			}
+			continue
+		case tablePowerSummary:
+			if fields[0] == "Total" && len(fields) > 1 {
+				p.env.Budget["used"] = util.Str2float64(fields[1])
+				if len(fields) > 2 && allNumbers(fields[2:3]) {
+					p.env.Budget["capacity"] = util.Str2float64(fields[2])
+				} else if matches := totalMaxRegexp.FindStringSubmatch(line); matches != nil {
+					p.env.Budget["capacity"] = util.Str2float64(matches[1])
+				}
+			}
+			continue
+		case tableNXPower:
+			p.parseNXOSPower(fields, nxosInput)
+			continue
+		case tableNXFan:
+			if matches := nxosFanNameRegexp.FindStringSubmatch(line); matches != nil && len(fields) > 1 {
+				p.fan(matches[1]).Status = fields[len(fields)-1]
+			}
+			continue
+		case tableNXFanDetail:
+			if matches := nxosFanNameRegexp.FindStringSubmatch(line); matches != nil && allNumbers(fields[len(fields)-1:]) {
+				f := p.fan(matches[1])
+				f.RPM, f.HasRPM = util.Str2float64(fields[len(fields)-1]), true
 			}
+			continue
+		case tableNXTemperature:
+			p.parseNXOSTemperature(fields)
+			continue
+		}
+
+		// Catalyst style status lines
+		if strings.HasPrefix(line, "Switch ") && strings.Contains(line, ":") {
+			currentSwitch = strings.Split(line, ":")[0]
+			continue
+		}
+		if matches := tempValueRegexp.FindStringSubmatch(line); matches != nil {
+			lastTemp = strings.TrimSpace(currentSwitch + " " + matches[1] + " Temperature Value")
+			t := p.temp(lastTemp)
+			t.Temperature = util.Str2float64(matches[2])
+			continue
+		}
+		if matches := tempStateRegexp.FindStringSubmatch(line); matches != nil && lastTemp != "" {
+			p.temp(lastTemp).Status = matches[1]
+			continue
 		}
+		if matches := tempThresholdRegexp.FindStringSubmatch(line); matches != nil && lastTemp != "" {
+			t := p.temp(lastTemp)
+			if matches[1] == "Yellow" {
+				t.Yellow, t.HasYellow = util.Str2float64(matches[2]), true
+			} else {
+				t.Red, t.HasRed = util.Str2float64(matches[2]), true
+			}
+			continue
+		}
+		if matches := fanStatusRegexp.FindStringSubmatch(line); matches != nil {
+			if matches[2] != "NOT PRESENT" {
+				p.fan(matches[1]).Status = matches[2]
+			}
+			continue
+		}
+		if matches := powerStatusRegexp.FindStringSubmatch(line); matches != nil && matches[2] != "NOT PRESENT" {
+			p.psu(matches[1]).Status = matches[2]
+		}
+	}
+
+	return p.env, nil
+}
+
+// parseStackPower parses a row of the Catalyst power supply table
+// (SW, PID, Serial#, Status, Sys Pwr, PoE Pwr, Watts)
+func (p *parser) parseStackPower(fields []string) {
+	if len(fields) < 4 || !stackPowerRegexp.MatchString(fields[0]) {
+		return
+	}
+	s := p.psu(fields[0])
+	s.Status = fields[3]
+	if len(fields) >= 7 && allNumbers(fields[len(fields)-1:]) {
+		s.Status = strings.Join(fields[3:len(fields)-3], " ")
+		s.Capacity, s.HasCapacity = util.Str2float64(fields[len(fields)-1]), true
+	}
+}
+
+// parseSensor parses a row of the sensor table of chassis based IOS XE platforms (ASR, ISR, Catalyst 9400/9500)
+func (p *parser) parseSensor(line string, slotFirst bool) {
+	matches := sensorRegexp.FindStringSubmatch(line)
+	if matches == nil {
+		return
+	}
+	slot, sensor := matches[2], matches[1]
+	if slotFirst {
+		// sensor names may contain spaces, the slot never does
+		parts := strings.SplitN(matches[1]+" "+matches[2], " ", 2)
+		slot, sensor = parts[0], strings.TrimSpace(parts[1])
+	}
+	state, value := matches[3], util.Str2float64(matches[4])
+
+	switch matches[5] {
+	case "Celsius":
+		t := p.temp(slot + " " + sensor)
+		t.Temperature = value
+		t.Status = state
+		if limits := sensorLimitsRegexp.FindStringSubmatch(matches[6]); limits != nil {
+			t.Yellow, t.HasYellow = util.Str2float64(limits[1]), true
+			t.Red, t.HasRed = util.Str2float64(limits[2]), true
+		}
+	case "RPM":
+		f := p.fan(slot + " " + sensor)
+		f.RPM, f.HasRPM = value, true
+		f.Status = state
+	case "W":
+		s := p.psu(slot)
+		if s.Status == "" || !statusOK(state) {
+			s.Status = state
+		}
+		if sensor == "Pin" || strings.Contains(sensor, "Input") {
+			s.InputWatts, s.HasInput = value, true
+		} else if sensor == "Pout" || strings.Contains(sensor, "Output") {
+			s.OutputWatts, s.HasOutput = value, true
+		}
+	}
+}
+
+// parseNXOSPower parses a row of the NX-OS power supply table
+// (Supply, Model, Actual Output, [Actual Input,] Total Capacity, Status)
+func (p *parser) parseNXOSPower(fields []string, hasInput bool) {
+	if len(fields) < 3 || !allNumbers(fields[:1]) {
+		return
+	}
+	s := p.psu("PS" + fields[0])
+	s.Status = fields[len(fields)-1]
+
+	watts := wattsRegexp.FindAllStringSubmatch(strings.Join(fields[2:], " "), -1)
+	if len(watts) == 0 {
+		return
+	}
+	s.OutputWatts, s.HasOutput = util.Str2float64(watts[0][1]), true
+	if hasInput && len(watts) > 2 {
+		s.InputWatts, s.HasInput = util.Str2float64(watts[1][1]), true
+	}
+	if len(watts) > 1 {
+		s.Capacity, s.HasCapacity = util.Str2float64(watts[len(watts)-1][1]), true
+	}
+}
+
+// parseNXOSTemperature parses a row of the NX-OS temperature table
+// (Module, Sensor, MajorThresh, MinorThres, CurTemp, Status)
+func (p *parser) parseNXOSTemperature(fields []string) {
+	n := len(fields)
+	if n < 6 || !allNumbers(fields[n-4:n-1]) {
+		return
+	}
+	t := p.temp(fields[0] + " " + strings.Join(fields[1:n-4], " "))
+	t.Red, t.HasRed = util.Str2float64(fields[n-4]), true
+	t.Yellow, t.HasYellow = util.Str2float64(fields[n-3]), true
+	t.Temperature = util.Str2float64(fields[n-2])
+	t.Status = fields[n-1]
+}
+
+func (p *parser) temp(name string) *TemperatureSensor {
+	i, ok := p.temps[name]
+	if !ok {
+		i = len(p.env.Temperatures)
+		p.temps[name] = i
+		p.env.Temperatures = append(p.env.Temperatures, TemperatureSensor{Name: name})
 	}
+	return &p.env.Temperatures[i]
+}
 
-	return items, nil
+func (p *parser) fan(name string) *Fan {
+	i, ok := p.fans[name]
+	if !ok {
+		i = len(p.env.Fans)
+		p.fans[name] = i
+		p.env.Fans = append(p.env.Fans, Fan{Name: name})
+	}
+	return &p.env.Fans[i]
+}
+
+func (p *parser) psu(name string) *PowerSupply {
+	i, ok := p.psus[name]
+	if !ok {
+		i = len(p.env.PowerSupplies)
+		p.psus[name] = i
+		p.env.PowerSupplies = append(p.env.PowerSupplies, PowerSupply{Name: name})
+	}
+	return &p.env.PowerSupplies[i]
+}
+
+// statusOK reports whether a status string of any of the supported platforms means the component is healthy
+func statusOK(status string) bool {
+	switch strings.ToLower(status) {
+	case "ok", "normal", "good", "green", "active", "on":
+		return true
+	}
+	return false
+}
+
+func allNumbers(values []string) bool {
+	for _, v := range values {
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return false
+		}
+	}
+	return true
 }
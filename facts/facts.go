@@ -4,6 +4,19 @@ type VersionFact struct {
 	Version string
 }
 
+// DeviceFact holds the identity of a device as reported by 'show version'
+type DeviceFact struct {
+	Hostname       string
+	Model          string
+	Serial         string
+	Image          string
+	ConfigRegister string
+	ReloadReason   string
+	License        string
+	Uptime         float64
+	HasUptime      bool
+}
+
 type MemoryFact struct {
 	Type  string
 	Total float64
@@ -12,8 +25,26 @@ type MemoryFact struct {
 }
 
 type CPUFact struct {
-	FiveSeconds float64
-	Interrupts  float64
-	OneMinute   float64
-	FiveMinutes float64
+	FiveSeconds    float64
+	Interrupts     float64
+	OneMinute      float64
+	FiveMinutes    float64
+	HasFiveSeconds bool
+	HasAverages    bool
+	HasInterrupts  bool
+}
+
+// ProcessCPUFact is the CPU utilization of one process per interval (5s, 1m, 5m on IOS, 1s on NX-OS)
+type ProcessCPUFact struct {
+	PID     string
+	Name    string
+	Percent map[string]float64
+	Current float64
+}
+
+// ProcessMemoryFact is the memory held by one process
+type ProcessMemoryFact struct {
+	PID     string
+	Name    string
+	Holding float64
 }
@@ -2,6 +2,7 @@ package facts
 
 import (
 	"log"
+	"time"
 
 	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
 
@@ -13,6 +14,8 @@ const prefix string = "cisco_facts_"
 
 var (
 	versionDesc        *prometheus.Desc
+	deviceInfoDesc     *prometheus.Desc
+	lastReloadDesc     *prometheus.Desc
 	memoryTotalDesc    *prometheus.Desc
 	memoryUsedDesc     *prometheus.Desc
 	memoryFreeDesc     *prometheus.Desc
@@ -25,6 +28,8 @@ var (
 func init() {
 	l := []string{"target"}
 	versionDesc = prometheus.NewDesc(prefix+"version", "Running OS version", append(l, "version"), nil)
+	deviceInfoDesc = prometheus.NewDesc(prefix+"device_info", "Hostname, model, serial number, image, config register, last reload reason and license level", append(l, "hostname", "model", "serial", "image", "config_register", "reload_reason", "license"), nil)
+	lastReloadDesc = prometheus.NewDesc(prefix+"last_reload_timestamp_seconds", "Time of the last reload as unix timestamp, truncated to the minute", l, nil)
 
 	memoryTotalDesc = prometheus.NewDesc(prefix+"memory_total", "Total memory", append(l, "type"), nil)
 	memoryUsedDesc = prometheus.NewDesc(prefix+"memory_used", "Used memory", append(l, "type"), nil)
@@ -52,6 +57,8 @@ func (*factsCollector) Name() string {
 // Describe describes the metrics
 func (*factsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- versionDesc
+	ch <- deviceInfoDesc
+	ch <- lastReloadDesc
 	ch <- memoryTotalDesc
 	ch <- memoryUsedDesc
 	ch <- memoryFreeDesc
@@ -69,6 +76,17 @@ func (c *factsCollector) CollectVersion(client *rpc.Client, ch chan<- prometheus
 	}
 	l := append(labelValues, item.Version)
 	ch <- prometheus.MustNewConstMetric(versionDesc, prometheus.GaugeValue, 1, l...)
+
+	device, err := c.ParseDevice(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(deviceInfoDesc, prometheus.GaugeValue, 1, append(labelValues, device.Hostname, device.Model, device.Serial, device.Image, device.ConfigRegister, device.ReloadReason, device.License)...)
+	if device.HasUptime {
+		// the uptime is reported in minutes, so the reload time is only exact to the minute and must not move between scrapes
+		reload := time.Now().Add(-time.Duration(device.Uptime) * time.Second).Truncate(time.Minute)
+		ch <- prometheus.MustNewConstMetric(lastReloadDesc, prometheus.GaugeValue, float64(reload.Unix()), labelValues...)
+	}
 	return nil
 }
 
@@ -101,10 +119,16 @@ func (c *factsCollector) CollectCPU(client *rpc.Client, ch chan<- prometheus.Met
 	if err != nil {
 		return err
 	}
-	ch <- prometheus.MustNewConstMetric(cpuOneMinuteDesc, prometheus.GaugeValue, item.OneMinute, labelValues...)
-	ch <- prometheus.MustNewConstMetric(cpuFiveSecondsDesc, prometheus.GaugeValue, item.FiveSeconds, labelValues...)
-	ch <- prometheus.MustNewConstMetric(cpuInterruptsDesc, prometheus.GaugeValue, item.Interrupts, labelValues...)
-	ch <- prometheus.MustNewConstMetric(cpuFiveMinutesDesc, prometheus.GaugeValue, item.FiveMinutes, labelValues...)
+	if item.HasFiveSeconds {
+		ch <- prometheus.MustNewConstMetric(cpuFiveSecondsDesc, prometheus.GaugeValue, item.FiveSeconds, labelValues...)
+	}
+	if item.HasAverages {
+		ch <- prometheus.MustNewConstMetric(cpuOneMinuteDesc, prometheus.GaugeValue, item.OneMinute, labelValues...)
+		ch <- prometheus.MustNewConstMetric(cpuFiveMinutesDesc, prometheus.GaugeValue, item.FiveMinutes, labelValues...)
+	}
+	if item.HasInterrupts {
+		ch <- prometheus.MustNewConstMetric(cpuInterruptsDesc, prometheus.GaugeValue, item.Interrupts, labelValues...)
+	}
 	return nil
 }
 
@@ -11,13 +11,15 @@ import (
 
 // ParseVersion parses cli output and tries to find the version number of the running OS
 func (c *factsCollector) ParseVersion(ostype string, output string) (VersionFact, error) {
-	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS && ostype != rpc.IOSXR && ostype != rpc.ASA {
 		return VersionFact{}, errors.New("'show version' is not implemented for " + ostype)
 	}
 	versionRegexp := make(map[string]*regexp.Regexp)
 	versionRegexp[rpc.IOSXE], _ = regexp.Compile(`^.*, Version (.+) -.*$`)
 	versionRegexp[rpc.IOS], _ = regexp.Compile(`^.*, Version (.+),.*$`)
 	versionRegexp[rpc.NXOS], _ = regexp.Compile(`^\s+NXOS: version (.*)$`)
+	versionRegexp[rpc.IOSXR], _ = regexp.Compile(`^Cisco IOS XR Software, Version (\S+)`)
+	versionRegexp[rpc.ASA], _ = regexp.Compile(`(?:Adaptive Security Appliance Software|Threat Defense .*) Version (\S+)`)
 
 	lines := strings.Split(output, "\n")
 	for _, line := range lines {
@@ -30,11 +32,138 @@ func (c *factsCollector) ParseVersion(ostype string, output string) (VersionFact
 	return VersionFact{}, errors.New("Version string not found")
 }
 
+// ParseDevice parses the output of 'show version' and returns hostname, model, serial number, image,
+// config register, last reload reason, license level and uptime of the device
+func (c *factsCollector) ParseDevice(ostype string, output string) (DeviceFact, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS && ostype != rpc.IOSXR && ostype != rpc.ASA {
+		return DeviceFact{}, errors.New("'show version' is not implemented for " + ostype)
+	}
+	if ostype == rpc.NXOS {
+		return parseDeviceNXOS(output), nil
+	}
+	if ostype == rpc.ASA {
+		return parseDeviceASA(output), nil
+	}
+
+	uptimeRegexp := regexp.MustCompile(`^(\S+) uptime is (.+)$`)
+	imageRegexp := regexp.MustCompile(`^System image file is "(.+)"`)
+	registerRegexp := regexp.MustCompile(`^Configuration register is (\S+)`)
+	reasonRegexp := regexp.MustCompile(`^Last reload reason:\s*(.+)$`)
+	returnedRegexp := regexp.MustCompile(`^System returned to ROM by (.+?)(?: at .*)?$`)
+	modelRegexp := regexp.MustCompile(`(?i)^Model Number\s*:\s*(\S+)`)
+	processorRegexp := regexp.MustCompile(`^[Cc]isco (\S+) .*(?:processor|bytes of memory)`)
+	serialRegexp := regexp.MustCompile(`(?i)^System Serial Number\s*:\s*(\S+)`)
+	boardRegexp := regexp.MustCompile(`^Processor board ID (\S+)`)
+	licenseRegexp := regexp.MustCompile(`^License Level:\s*(\S+)`)
+
+	item := DeviceFact{}
+	licenses := []string{}
+	licenseColumn := -1
+	// stacks repeat model and serial number for every member, the first one is the active switch
+	modelNumber, serialNumber := false, false
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			licenseColumn = -1
+			continue
+		}
+		if licenseColumn < 0 && strings.Contains(line, "Technology-package") {
+			// Catalyst lists the current package first, ISR lists the technology before it
+			licenseColumn = 1
+			if fields[0] == "Technology-package" {
+				licenseColumn = 0
+			}
+			continue
+		}
+
+		switch {
+		case uptimeRegexp.MatchString(line) && !item.HasUptime:
+			matches := uptimeRegexp.FindStringSubmatch(line)
+			// recent IOS XR releases print 'System uptime is' without the hostname
+			if matches[1] != "System" {
+				item.Hostname = matches[1]
+			}
+			item.Uptime, item.HasUptime = util.Duration2Seconds(matches[2]), true
+		case imageRegexp.MatchString(line):
+			item.Image = imageRegexp.FindStringSubmatch(line)[1]
+		case registerRegexp.MatchString(line):
+			item.ConfigRegister = registerRegexp.FindStringSubmatch(line)[1]
+		case reasonRegexp.MatchString(line):
+			item.ReloadReason = reasonRegexp.FindStringSubmatch(line)[1]
+		case returnedRegexp.MatchString(line) && item.ReloadReason == "":
+			item.ReloadReason = returnedRegexp.FindStringSubmatch(line)[1]
+		case modelRegexp.MatchString(line):
+			if !modelNumber {
+				item.Model, modelNumber = modelRegexp.FindStringSubmatch(line)[1], true
+			}
+		case processorRegexp.MatchString(line) && item.Model == "":
+			item.Model = processorRegexp.FindStringSubmatch(line)[1]
+		case serialRegexp.MatchString(line):
+			if !serialNumber {
+				item.Serial, serialNumber = serialRegexp.FindStringSubmatch(line)[1], true
+			}
+		case boardRegexp.MatchString(line) && item.Serial == "":
+			item.Serial = boardRegexp.FindStringSubmatch(line)[1]
+		case licenseRegexp.MatchString(line):
+			item.License = licenseRegexp.FindStringSubmatch(line)[1]
+		case licenseColumn >= 0 && len(fields) > licenseColumn && !strings.HasPrefix(line, "---") && fields[0] != "Current":
+			if current := fields[licenseColumn]; current != "None" {
+				licenses = append(licenses, current)
+			}
+		}
+	}
+	if item.License == "" {
+		item.License = strings.Join(licenses, ",")
+	}
+	return item, nil
+}
+
+func parseDeviceNXOS(output string) DeviceFact {
+	uptimeRegexp := regexp.MustCompile(`^Kernel uptime is (.+)$`)
+	hostnameRegexp := regexp.MustCompile(`^Device name:\s*(\S+)`)
+	imageRegexp := regexp.MustCompile(`^(?:NXOS|system) image file is:\s*(\S+)`)
+	chassisRegexp := regexp.MustCompile(`^cisco (.+?) [Cc]hassis`)
+	boardRegexp := regexp.MustCompile(`^Processor Board ID (\S+)`)
+	reasonRegexp := regexp.MustCompile(`^Reason:\s*(.+)$`)
+
+	item := DeviceFact{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case uptimeRegexp.MatchString(line):
+			item.Uptime, item.HasUptime = util.Duration2Seconds(uptimeRegexp.FindStringSubmatch(line)[1]), true
+		case hostnameRegexp.MatchString(line):
+			item.Hostname = hostnameRegexp.FindStringSubmatch(line)[1]
+		case imageRegexp.MatchString(line):
+			item.Image = imageRegexp.FindStringSubmatch(line)[1]
+		case chassisRegexp.MatchString(line) && item.Model == "":
+			item.Model = chassisRegexp.FindStringSubmatch(line)[1]
+		case boardRegexp.MatchString(line):
+			item.Serial = boardRegexp.FindStringSubmatch(line)[1]
+		case reasonRegexp.MatchString(line) && item.ReloadReason == "":
+			item.ReloadReason = reasonRegexp.FindStringSubmatch(line)[1]
+		}
+	}
+	return item
+}
+
 // ParseMemory parses cli output and tries to find current memory usage
 func (c *factsCollector) ParseMemory(ostype string, output string) ([]MemoryFact, error) {
-	if ostype != rpc.IOSXE && ostype != rpc.IOS {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS && ostype != rpc.IOSXR && ostype != rpc.ASA {
 		return nil, errors.New("'show process memory' is not implemented for " + ostype)
 	}
+	if ostype == rpc.IOSXR {
+		return parseMemoryIOSXR(output)
+	}
+	if ostype == rpc.NXOS {
+		return parseMemoryNXOS(output)
+	}
+	if ostype == rpc.ASA {
+		return parseMemoryASA(output)
+	}
 	memoryRegexp, _ := regexp.Compile(`^\s*(\S*) Pool Total:\s*(\d+) Used:\s*(\d+) Free:\s*(\d+)\s*$`)
 
 	items := []MemoryFact{}
@@ -57,9 +186,18 @@ func (c *factsCollector) ParseMemory(ostype string, output string) ([]MemoryFact
 
 // ParseCPU parses cli output and tries to find current CPU utilization
 func (c *factsCollector) ParseCPU(ostype string, output string) (CPUFact, error) {
-	if ostype != rpc.IOSXE && ostype != rpc.IOS {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS && ostype != rpc.IOSXR && ostype != rpc.ASA {
 		return CPUFact{}, errors.New("'show process cpu' is not implemented for " + ostype)
 	}
+	if ostype == rpc.IOSXR {
+		return parseCPUIOSXR(output)
+	}
+	if ostype == rpc.NXOS {
+		return parseCPUNXOS(output)
+	}
+	if ostype == rpc.ASA {
+		return parseCPUASA(output)
+	}
 	memoryRegexp, _ := regexp.Compile(`^\s*CPU utilization for five seconds: (\d+)%\/(\d+)%; one minute: (\d+)%; five minutes: (\d+)%.*$`)
 
 	lines := strings.Split(output, "\n")
@@ -69,11 +207,253 @@ func (c *factsCollector) ParseCPU(ostype string, output string) (CPUFact, error)
 			continue
 		}
 		return CPUFact{
-			FiveSeconds: util.Str2float64(matches[1]),
-			Interrupts:  util.Str2float64(matches[2]),
-			OneMinute:   util.Str2float64(matches[3]),
-			FiveMinutes: util.Str2float64(matches[4]),
+			FiveSeconds:    util.Str2float64(matches[1]),
+			Interrupts:     util.Str2float64(matches[2]),
+			OneMinute:      util.Str2float64(matches[3]),
+			FiveMinutes:    util.Str2float64(matches[4]),
+			HasFiveSeconds: true,
+			HasAverages:    true,
+			HasInterrupts:  true,
 		}, nil
 	}
 	return CPUFact{}, errors.New("Version string not found")
 }
+
+// parseMemoryNXOS parses the memory usage of 'show system resources' (NX-OS)
+func parseMemoryNXOS(output string) ([]MemoryFact, error) {
+	memoryRegexp := regexp.MustCompile(`^\s*Memory usage:\s*(\d+)K total,\s*(\d+)K used,\s*(\d+)K free`)
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := memoryRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		return []MemoryFact{{
+			Type:  "System",
+			Total: util.Str2float64(matches[1]) * 1024,
+			Used:  util.Str2float64(matches[2]) * 1024,
+			Free:  util.Str2float64(matches[3]) * 1024,
+		}}, nil
+	}
+	return nil, errors.New("Memory usage not found")
+}
+
+// parseCPUNXOS parses the CPU utilization of 'show processes cpu' (NX-OS), which only reports the current value
+func parseCPUNXOS(output string) (CPUFact, error) {
+	cpuRegexp := regexp.MustCompile(`^\s*CPU (?:util|states)\s*:\s*([\d.]+)% user,\s*([\d.]+)% kernel`)
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := cpuRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		return CPUFact{
+			FiveSeconds:    util.Str2float64(matches[1]) + util.Str2float64(matches[2]),
+			HasFiveSeconds: true,
+		}, nil
+	}
+	return CPUFact{}, errors.New("CPU utilization not found")
+}
+
+// parseMemoryIOSXR parses the physical memory of the node in 'show memory summary' (IOS XR)
+func parseMemoryIOSXR(output string) ([]MemoryFact, error) {
+	memoryRegexp := regexp.MustCompile(`^\s*Physical Memory:\s*([\d.]+)M total \(([\d.]+)M available\)`)
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := memoryRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		total := util.Str2float64(matches[1]) * 1024 * 1024
+		free := util.Str2float64(matches[2]) * 1024 * 1024
+		return []MemoryFact{{
+			Type:  "Physical",
+			Total: total,
+			Used:  total - free,
+			Free:  free,
+		}}, nil
+	}
+	return nil, errors.New("Memory usage not found")
+}
+
+// parseCPUIOSXR parses the output of 'show processes cpu' (IOS XR), which averages over one, five and fifteen minutes
+func parseCPUIOSXR(output string) (CPUFact, error) {
+	cpuRegexp := regexp.MustCompile(`CPU utilization for one minute: (\d+)%; five minutes: (\d+)%`)
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := cpuRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		return CPUFact{
+			OneMinute:   util.Str2float64(matches[1]),
+			FiveMinutes: util.Str2float64(matches[2]),
+			HasAverages: true,
+		}, nil
+	}
+	return CPUFact{}, errors.New("CPU utilization not found")
+}
+
+// parseDeviceASA parses the output of 'show version' of ASA and FTD
+func parseDeviceASA(output string) DeviceFact {
+	uptimeRegexp := regexp.MustCompile(`^(\S+) up (.+)$`)
+	ftdHostnameRegexp := regexp.MustCompile(`^-+\[ (\S+) \]-+$`)
+	imageRegexp := regexp.MustCompile(`^System image file is "(.+)"`)
+	registerRegexp := regexp.MustCompile(`^Configuration register is (\S+)`)
+	hardwareRegexp := regexp.MustCompile(`^Hardware:\s*([^,]+),`)
+	ftdModelRegexp := regexp.MustCompile(`^Model\s*:\s*Cisco (.+?) Threat Defense`)
+	serialRegexp := regexp.MustCompile(`^Serial Number:\s*(\S+)`)
+	licenseRegexp := regexp.MustCompile(`^License mode:\s*(.+)$`)
+
+	item := DeviceFact{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case ftdHostnameRegexp.MatchString(line):
+			item.Hostname = ftdHostnameRegexp.FindStringSubmatch(line)[1]
+		case uptimeRegexp.MatchString(line) && !item.HasUptime:
+			matches := uptimeRegexp.FindStringSubmatch(line)
+			item.Hostname = matches[1]
+			item.Uptime, item.HasUptime = util.Duration2Seconds(matches[2]), true
+		case imageRegexp.MatchString(line):
+			item.Image = imageRegexp.FindStringSubmatch(line)[1]
+		case registerRegexp.MatchString(line):
+			item.ConfigRegister = registerRegexp.FindStringSubmatch(line)[1]
+		case ftdModelRegexp.MatchString(line):
+			item.Model = ftdModelRegexp.FindStringSubmatch(line)[1]
+		case hardwareRegexp.MatchString(line) && item.Model == "":
+			item.Model = hardwareRegexp.FindStringSubmatch(line)[1]
+		case serialRegexp.MatchString(line):
+			item.Serial = serialRegexp.FindStringSubmatch(line)[1]
+		case licenseRegexp.MatchString(line):
+			item.License = licenseRegexp.FindStringSubmatch(line)[1]
+		}
+	}
+	return item
+}
+
+// parseMemoryASA parses the output of 'show memory' (ASA)
+func parseMemoryASA(output string) ([]MemoryFact, error) {
+	memoryRegexp := regexp.MustCompile(`^(Free|Used|Total) memory:\s*(\d+) bytes`)
+
+	item := MemoryFact{Type: "System"}
+	found := false
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := memoryRegexp.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		found = true
+		switch matches[1] {
+		case "Free":
+			item.Free = util.Str2float64(matches[2])
+		case "Used":
+			item.Used = util.Str2float64(matches[2])
+		case "Total":
+			item.Total = util.Str2float64(matches[2])
+		}
+	}
+	if !found {
+		return nil, errors.New("Memory usage not found")
+	}
+	return []MemoryFact{item}, nil
+}
+
+// parseCPUASA parses the output of 'show cpu usage' (ASA), which has no interrupt share
+func parseCPUASA(output string) (CPUFact, error) {
+	cpuRegexp := regexp.MustCompile(`CPU utilization for 5 seconds = ([\d.]+)%; 1 minute: ([\d.]+)%; 5 minutes: ([\d.]+)%`)
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := cpuRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		return CPUFact{
+			FiveSeconds:    util.Str2float64(matches[1]),
+			OneMinute:      util.Str2float64(matches[2]),
+			FiveMinutes:    util.Str2float64(matches[3]),
+			HasFiveSeconds: true,
+			HasAverages:    true,
+		}, nil
+	}
+	return CPUFact{}, errors.New("CPU utilization not found")
+}
+
+// ParseProcessCPU parses the per-process CPU utilization of 'show processes cpu sorted' (IOS, IOS XE)
+// or 'show processes cpu sort' (NX-OS)
+func ParseProcessCPU(ostype string, output string) ([]ProcessCPUFact, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return nil, errors.New("'show processes cpu sorted' is not implemented for " + ostype)
+	}
+	iosRegexp := regexp.MustCompile(`^\s*(\d+)\s+\d+\s+\d+\s+\d+\s+([\d.]+)%\s+([\d.]+)%\s+([\d.]+)%\s+\d+\s+(.+?)\s*$`)
+	nxosRegexp := regexp.MustCompile(`^\s*(\d+)\s+\d+\s+\d+\s+\d+\s+([\d.]+)%\s+(.+?)\s*$`)
+
+	items := []ProcessCPUFact{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if ostype == rpc.NXOS {
+			matches := nxosRegexp.FindStringSubmatch(line)
+			if matches == nil {
+				continue
+			}
+			current := util.Str2float64(matches[2])
+			items = append(items, ProcessCPUFact{
+				PID:     matches[1],
+				Name:    matches[3],
+				Percent: map[string]float64{"1s": current},
+				Current: current,
+			})
+			continue
+		}
+		matches := iosRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		current := util.Str2float64(matches[2])
+		items = append(items, ProcessCPUFact{
+			PID:  matches[1],
+			Name: matches[5],
+			Percent: map[string]float64{
+				"5s": current,
+				"1m": util.Str2float64(matches[3]),
+				"5m": util.Str2float64(matches[4]),
+			},
+			Current: current,
+		})
+	}
+	return items, nil
+}
+
+// ParseProcessMemory parses the per-process memory of 'show processes memory sorted' (IOS, IOS XE)
+// or 'show processes memory' (NX-OS)
+func ParseProcessMemory(ostype string, output string) ([]ProcessMemoryFact, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return nil, errors.New("'show processes memory sorted' is not implemented for " + ostype)
+	}
+	memoryRegexp := regexp.MustCompile(`^\s*(\d+)\s+\d+\s+\d+\s+\d+\s+(\d+)\s+\d+\s+\d+\s+(.+?)\s*$`)
+	if ostype == rpc.NXOS {
+		memoryRegexp = regexp.MustCompile(`^\s*(\d+)\s+\d+\s+\d+\s+(\d+)\s+\S+/\S+\s+(.+?)\s*$`)
+	}
+
+	items := []ProcessMemoryFact{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := memoryRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		items = append(items, ProcessMemoryFact{
+			PID:     matches[1],
+			Name:    matches[3],
+			Holding: util.Str2float64(matches[2]),
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,92 @@
+package facts
+
+import (
+	"log"
+	"sort"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	processCPUDesc    *prometheus.Desc
+	processMemoryDesc *prometheus.Desc
+)
+
+func init() {
+	l := []string{"target", "pid", "process"}
+	processCPUDesc = prometheus.NewDesc(prefix+"process_cpu_percent", "CPU utilization of the top processes per interval (5s, 1m, 5m; 1s on NX-OS)", append(l, "interval"), nil)
+	processMemoryDesc = prometheus.NewDesc(prefix+"process_memory_holding_bytes", "Memory held by the top processes in bytes", l, nil)
+}
+
+type processCollector struct {
+	topN int
+}
+
+// NewProcessCollector creates a new collector exporting the top N processes by CPU and memory usage
+func NewProcessCollector(topN int) collector.RPCCollector {
+	return &processCollector{topN: topN}
+}
+
+// Name returns the name of the collector
+func (*processCollector) Name() string {
+	return "FactsProcesses"
+}
+
+// Describe describes the metrics
+func (*processCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- processCPUDesc
+	ch <- processMemoryDesc
+}
+
+// Collect collects metrics from Cisco
+func (c *processCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	cpuCmd, memoryCmd := "show processes cpu sorted", "show processes memory sorted"
+	if client.OSType == rpc.NXOS {
+		cpuCmd, memoryCmd = "show processes cpu sort", "show processes memory"
+	}
+
+	out, err := client.RunCommand(cpuCmd)
+	if err != nil {
+		return err
+	}
+	cpu, err := ParseProcessCPU(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("ParseProcessCPU for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+	sort.SliceStable(cpu, func(i, j int) bool { return cpu[i].Current > cpu[j].Current })
+	for i, p := range cpu {
+		if i >= c.topN {
+			break
+		}
+		l := append(labelValues, p.PID, p.Name)
+		for interval, percent := range p.Percent {
+			ch <- prometheus.MustNewConstMetric(processCPUDesc, prometheus.GaugeValue, percent, append(l, interval)...)
+		}
+	}
+
+	out, err = client.RunCommand(memoryCmd)
+	if err != nil {
+		return err
+	}
+	memory, err := ParseProcessMemory(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("ParseProcessMemory for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+	sort.SliceStable(memory, func(i, j int) bool { return memory[i].Holding > memory[j].Holding })
+	for i, p := range memory {
+		if i >= c.topN {
+			break
+		}
+		ch <- prometheus.MustNewConstMetric(processMemoryDesc, prometheus.GaugeValue, p.Holding, append(labelValues, p.PID, p.Name)...)
+	}
+
+	return nil
+}
@@ -11,7 +11,7 @@ import (
 
 // Parse parses cli output and tries to find interfaces with related stats
 func (c *interfaceCollector) Parse(ostype string, output string) ([]Interface, error) {
-	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS && ostype != rpc.ASA {
 		return nil, errors.New("'show interface' is not implemented for " + ostype)
 	}
 	items := []Interface{}
@@ -19,6 +19,10 @@ func (c *interfaceCollector) Parse(ostype string, output string) ([]Interface, e
 	newIfRegexp := regexp.MustCompile(`(?:^!?(?: |admin|show|.+#).*$|^$)`)
 	macRegexp := regexp.MustCompile(`^\s+Hardware(?: is|:) .+, address(?: is|:) (.*) \(.*\)$`)
 	deviceNameRegexp := regexp.MustCompile(`^([a-zA-Z0-9\/\.-]+) is.*$`)
+	deviceNameASARegexp := regexp.MustCompile(`^Interface ([a-zA-Z0-9\/\.-]+) "([^"]*)", is.*$`)  // ASA
+	macASARegexp := regexp.MustCompile(`^\s+MAC address ([0-9a-f.]+), MTU.*$`)                    // ASA
+	speedASARegexp := regexp.MustCompile(`^\s+.*Duplex\(.*\), (?:Auto-)?Speed\((\d+) (\w)bps\)$`) // ASA
+	trafficASARegexp := regexp.MustCompile(`^\s+Traffic Statistics for ".*":$`)                   // ASA
 	adminStatusRegexp := regexp.MustCompile(`^.+ is (administratively)?\s*(up|down).*, line protocol is.*$`)
 	adminStatusNXOSRegexp := regexp.MustCompile(`^\S+ is (up|down)(?:\s|,)?(\(Administratively down\))?.*$`)
 	descRegexp := regexp.MustCompile(`^\s+Description: (.*)$`)
@@ -33,6 +37,7 @@ func (c *interfaceCollector) Parse(ostype string, output string) ([]Interface, e
 	speedRegexp := regexp.MustCompile(`^\s+(.*)-duplex,\s(\d+) ((\wb)/s).*$`)
 
 	isRx := true
+	isTraffic := false
 	current := Interface{}
 	lines := strings.Split(output, "\n")
 	for _, line := range lines {
@@ -40,18 +45,30 @@ func (c *interfaceCollector) Parse(ostype string, output string) ([]Interface, e
 			if current != (Interface{}) {
 				items = append(items, current)
 			}
-			matches := deviceNameRegexp.FindStringSubmatch(line)
-			if matches == nil {
+			current = Interface{}
+			if matches := deviceNameASARegexp.FindStringSubmatch(line); matches != nil {
+				// the nameif stands in for the description until a description line is found
+				current = Interface{
+					Name:        matches[1],
+					Description: matches[2],
+				}
+			} else if matches := deviceNameRegexp.FindStringSubmatch(line); matches != nil {
+				current = Interface{
+					Name: matches[1],
+				}
+			} else {
 				continue
 			}
-			current = Interface{
-				Name: matches[1],
-			}
 			isRx = true
+			isTraffic = false
 		}
 		if current == (Interface{}) {
 			continue
 		}
+		if isTraffic {
+			// the per-nameif traffic statistics of ASA repeat the hardware counters
+			continue
+		}
 
 		if matches := adminStatusRegexp.FindStringSubmatch(line); matches != nil {
 			if matches[1] == "" {
@@ -71,6 +88,10 @@ func (c *interfaceCollector) Parse(ostype string, output string) ([]Interface, e
 			current.Description = matches[1]
 		} else if matches := macRegexp.FindStringSubmatch(line); matches != nil {
 			current.MacAddress = matches[1]
+		} else if matches := macASARegexp.FindStringSubmatch(line); matches != nil {
+			current.MacAddress = matches[1]
+		} else if trafficASARegexp.MatchString(line) {
+			isTraffic = true
 		} else if matches := dropsRegexp.FindStringSubmatch(line); matches != nil {
 			current.InputDrops = util.Str2float64(matches[1])
 			current.OutputDrops = util.Str2float64(matches[2])
@@ -84,6 +105,8 @@ func (c *interfaceCollector) Parse(ostype string, output string) ([]Interface, e
 			current.OutputErrors = util.Str2float64(matches[1])
 		} else if matches := speedRegexp.FindStringSubmatch(line); matches != nil {
 			current.Speed = matches[2] + " " + matches[3]
+		} else if matches := speedASARegexp.FindStringSubmatch(line); matches != nil {
+			current.Speed = matches[1] + " " + matches[2] + "b/s"
 		} else if matches := txNXOS.FindStringSubmatch(line); matches != nil {
 			isRx = false
 		} else if matches := multiBroadNXOS.FindStringSubmatch(line); matches != nil {
@@ -0,0 +1,68 @@
+package inventory
+
+import (
+	"log"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prefix string = "cisco_inventory_"
+
+var (
+	infoDesc     *prometheus.Desc
+	pidCountDesc *prometheus.Desc
+)
+
+func init() {
+	l := []string{"target"}
+	infoDesc = prometheus.NewDesc(prefix+"info", "Hardware inventory item (chassis, module, power supply, fan, transceiver)", append(l, "name", "description", "pid", "vid", "serial"), nil)
+	pidCountDesc = prometheus.NewDesc(prefix+"pid_count", "Number of inventory items per product ID", append(l, "pid"), nil)
+}
+
+type inventoryCollector struct{}
+
+// NewCollector creates a new collector
+func NewCollector() collector.RPCCollector {
+	return &inventoryCollector{}
+}
+
+// Name returns the name of the collector
+func (*inventoryCollector) Name() string {
+	return "Inventory"
+}
+
+// Describe describes the metrics
+func (*inventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- infoDesc
+	ch <- pidCountDesc
+}
+
+// Collect collects metrics from Cisco
+func (c *inventoryCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show inventory")
+	if err != nil {
+		return err
+	}
+	items, err := Parse(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("Parse inventory for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+
+	counts := make(map[string]float64)
+	for _, item := range items {
+		ch <- prometheus.MustNewConstMetric(infoDesc, prometheus.GaugeValue, 1, append(labelValues, item.Name, item.Description, item.PID, item.VID, item.Serial)...)
+		if item.PID != "" {
+			counts[item.PID]++
+		}
+	}
+	for pid, count := range counts {
+		ch <- prometheus.MustNewConstMetric(pidCountDesc, prometheus.GaugeValue, count, append(labelValues, pid)...)
+	}
+
+	return nil
+}
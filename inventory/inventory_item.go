@@ -0,0 +1,10 @@
+package inventory
+
+// InventoryItem is a field replaceable unit listed by 'show inventory'
+type InventoryItem struct {
+	Name        string
+	Description string
+	PID         string
+	VID         string
+	Serial      string
+}
@@ -0,0 +1,48 @@
+package inventory
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+)
+
+var (
+	nameRegexp = regexp.MustCompile(`NAME:\s*"([^"]*)"\s*,\s*DESCR:\s*"([^"]*)"`)
+	pidRegexp  = regexp.MustCompile(`PID:\s*([^,]*?)\s*,\s*VID:\s*([^,]*?)\s*,\s*SN:\s*(\S*)`)
+)
+
+// Parse parses the output of 'show inventory' and returns the chassis, modules, power supplies,
+// fans and transceivers of the device
+func Parse(ostype string, output string) ([]InventoryItem, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS && ostype != rpc.IOSXR {
+		return nil, errors.New("'show inventory' is not implemented for " + ostype)
+	}
+
+	items := []InventoryItem{}
+	var current *InventoryItem
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if matches := nameRegexp.FindStringSubmatch(line); matches != nil {
+			current = &InventoryItem{
+				Name:        strings.TrimSpace(matches[1]),
+				Description: strings.TrimSpace(matches[2]),
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if matches := pidRegexp.FindStringSubmatch(line); matches != nil {
+			current.PID = matches[1]
+			current.VID = matches[2]
+			current.Serial = matches[3]
+			items = append(items, *current)
+			current = nil
+		}
+	}
+
+	return items, nil
+}
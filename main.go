@@ -7,9 +7,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/moeinshahcheraghi/cisco_exporter/config"
 	"github.com/moeinshahcheraghi/cisco_exporter/connector"
+	"github.com/moeinshahcheraghi/cisco_exporter/secret"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
@@ -23,23 +25,36 @@ var (
 	metricsPath        = flag.String("web.telemetry-path", "/metrics", "Path under which to expose metrics.")
 	sshHosts           = flag.String("ssh.targets", "", "SSH Hosts to scrape")
 	sshUsername        = flag.String("ssh.user", "cisco_exporter", "Username to use for SSH connection")
-	sshPassword        = flag.String("ssh.password", "", "Password to use for SSH connection")
+	sshPassword        = flag.String("ssh.password", "", "Password to use for SSH connection, preferably a reference (env:NAME, file:/path or vault:path#field) not showing up in the process list")
 	sshKeyFile         = flag.String("ssh.keyfile", "", "Key file to use for SSH connection")
+	sshProxy           = flag.String("ssh.proxy", "", "Proxy URL to connect to the devices through (socks5://, socks5h:// or http://)")
+	sshEnablePassword  = flag.String("ssh.enable-password", "", "Enable password for devices logging in at a '>' prompt")
 	sshTimeout         = flag.Int("ssh.timeout", 5, "Timeout to use for SSH connection")
 	sshBatchSize       = flag.Int("ssh.batch-size", 10000, "The SSH response batch size")
+	secretRefresh      = flag.Int("secret.refresh-interval", 300, "Seconds after which credentials referenced by env:, file: or vault: are resolved again")
 	debug              = flag.Bool("debug", false, "Show verbose debug output in log")
 	legacyCiphers      = flag.Bool("legacy.ciphers", false, "Allow legacy CBC ciphers")
 	bgpEnabled         = flag.Bool("bgp.enabled", true, "Scrape bgp metrics")
 	environmentEnabled = flag.Bool("environment.enabled", true, "Scrape environment metrics")
 	factsEnabled       = flag.Bool("facts.enabled", true, "Scrape system metrics")
+	processesEnabled   = flag.Bool("facts.processes.enabled", false, "Scrape CPU and memory usage of the top processes")
+	processTopN        = flag.Int("facts.processes.top-n", 10, "Number of processes to export per device")
 	interfacesEnabled  = flag.Bool("interfaces.enabled", true, "Scrape interface metrics")
 	opticsEnabled      = flag.Bool("optics.enabled", true, "Scrape optic metrics")
 	stackportEnabled   = flag.Bool("stackport.enabled", true, "Scrape stack port metrics")
+	stackMemberEnabled = flag.Bool("stackmembers.enabled", true, "Scrape stack member and stack power metrics")
 	uptimeEnabled      = flag.Bool("uptime.enabled", true, "Scrape uptime metrics")
 	stpEnabled         = flag.Bool("stp.enabled", true, "Scrape spanning tree metrics")
 	vlanEnabled        = flag.Bool("vlan.enabled", true, "Scrape VLAN metrics")
 	qosEnabled         = flag.Bool("qos.enabled", true, "Scrape QoS metrics")
+	qosQueuesEnabled   = flag.Bool("qos.queues.enabled", false, "Scrape hardware queue metrics (one command per up interface on IOS XE)")
 	aclEnabled         = flag.Bool("acl.enabled", true, "Scrape ACL metrics")
+	inventoryEnabled   = flag.Bool("inventory.enabled", true, "Scrape hardware inventory metrics")
+	moduleEnabled      = flag.Bool("module.enabled", true, "Scrape module status metrics")
+	redundancyEnabled  = flag.Bool("redundancy.enabled", true, "Scrape supervisor redundancy metrics")
+	asaEnabled         = flag.Bool("asa.enabled", true, "Scrape ASA/FTD firewall metrics (connections, xlates, failover, VPN sessions, ASP drops)")
+	wlcEnabled         = flag.Bool("wlc.enabled", true, "Scrape wireless LAN controller metrics (APs, clients, WLANs, radios)")
+	wlcChannelsEnabled = flag.Bool("wlc.channel-utilization.enabled", false, "Scrape channel utilization of the radios (two commands per access point)")
 	configFile         = flag.String("config.file", "", "Path to config file")
 	devices            []*connector.Device
 	cfg                *config.Config
@@ -90,6 +105,8 @@ func initialize() error {
 		return err
 	}
 
+	secret.SetRefreshInterval(time.Duration(c.SecretRefresh) * time.Second)
+
 	devices, err = devicesForConfig(c)
 	if err != nil {
 		return err
@@ -109,21 +126,34 @@ func loadConfigFromFlags() *config.Config {
 	c.Username = *sshUsername
 	c.Password = *sshPassword
 	c.KeyFile = *sshKeyFile
+	c.EnablePassword = *sshEnablePassword
+	c.Proxy = *sshProxy
+	c.ProcessTopN = *processTopN
+	c.SecretRefresh = *secretRefresh
 
 	c.DevicesFromTargets(*sshHosts)
 
 	f := c.Features
 	f.StackPort = stackportEnabled
+	f.StackMembers = stackMemberEnabled
 	f.BGP = bgpEnabled
 	f.Environment = environmentEnabled
 	f.Facts = factsEnabled
+	f.FactsProcesses = processesEnabled
 	f.Interfaces = interfacesEnabled
 	f.Optics = opticsEnabled
 	f.Uptime = uptimeEnabled
 	f.STP = stpEnabled
 	f.VLAN = vlanEnabled
 	f.QoS = qosEnabled
+	f.QoSQueues = qosQueuesEnabled
 	f.ACL = aclEnabled
+	f.Inventory = inventoryEnabled
+	f.Module = moduleEnabled
+	f.Redundancy = redundancyEnabled
+	f.ASA = asaEnabled
+	f.WLC = wlcEnabled
+	f.WLCChannels = wlcChannelsEnabled
 
 	return c
 }
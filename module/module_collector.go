@@ -0,0 +1,81 @@
+package module
+
+import (
+	"log"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prefix string = "cisco_module_"
+
+var (
+	statusDesc *prometheus.Desc
+	diagDesc   *prometheus.Desc
+	roleDesc   *prometheus.Desc
+)
+
+func init() {
+	l := []string{"target", "slot"}
+	statusDesc = prometheus.NewDesc(prefix+"status", "Status of a module (1 ok/online, 2 powered down, 3 failed, 0 other)", append(l, "type", "model", "status"), nil)
+	diagDesc = prometheus.NewDesc(prefix+"diag_passed", "Online diagnostic result of a module (1 Pass, 0 otherwise)", append(l, "result"), nil)
+	roleDesc = prometheus.NewDesc(prefix+"redundancy_role", "Redundancy role of a supervisor module (1 active, 0 standby)", append(l, "role"), nil)
+}
+
+type moduleCollector struct{}
+
+// NewCollector creates a new collector
+func NewCollector() collector.RPCCollector {
+	return &moduleCollector{}
+}
+
+// Name returns the name of the collector
+func (*moduleCollector) Name() string {
+	return "Module"
+}
+
+// Describe describes the metrics
+func (*moduleCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- statusDesc
+	ch <- diagDesc
+	ch <- roleDesc
+}
+
+// Collect collects metrics from Cisco
+func (c *moduleCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show module")
+	if err != nil {
+		return err
+	}
+	modules, err := Parse(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("Parse module for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+
+	for _, m := range modules {
+		l := append(labelValues, m.Slot)
+		if m.Status != "" {
+			ch <- prometheus.MustNewConstMetric(statusDesc, prometheus.GaugeValue, m.StatusValue(), append(l, m.Type, m.Model, m.Status)...)
+		}
+		if m.Diag != "" {
+			passed := 0.0
+			if m.Diag == "Pass" {
+				passed = 1
+			}
+			ch <- prometheus.MustNewConstMetric(diagDesc, prometheus.GaugeValue, passed, append(l, m.Diag)...)
+		}
+		if m.Role != "" {
+			active := 0.0
+			if m.Role == "active" {
+				active = 1
+			}
+			ch <- prometheus.MustNewConstMetric(roleDesc, prometheus.GaugeValue, active, append(l, m.Role)...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package module
+
+// Module is a line card, supervisor or fabric module in a slot of a modular chassis
+type Module struct {
+	Slot   string
+	Type   string
+	Model  string
+	Status string
+	Diag   string
+	Role   string
+}
+
+const (
+	statusOther       = 0
+	statusOK          = 1
+	statusPoweredDown = 2
+	statusFailed      = 3
+)
+
+// StatusValue maps the status reported by the device to 1 (ok/online), 2 (powered down), 3 (failed) or 0 (other, e.g. booting)
+func (m Module) StatusValue() float64 {
+	switch m.Status {
+	case "ok", "Ok", "OK", "active", "standby", "ha-standby", "online", "Online":
+		return statusOK
+	case "powered-dn", "pwr-denied", "pwr-off", "PwrDown", "PwrOff", "disabled", "off", "Off", "poweroff":
+		return statusPoweredDown
+	case "failure", "failed", "Failed", "faulty", "Faulty", "err-disable", "ErrDisable", "fail", "Fail", "MajorFault", "MinorFault":
+		return statusFailed
+	}
+	return statusOther
+}
@@ -0,0 +1,156 @@
+package module
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+)
+
+var (
+	separatorRegexp = regexp.MustCompile(`^[-+ ]*---[-+ ]*$`)
+	columnRegexp    = regexp.MustCompile(`-+`)
+	cardRoleRegexp  = regexp.MustCompile(`\((Active|Hot|Cold|Warm|Standby)\)`)
+)
+
+type column struct {
+	name  string
+	start int
+	end   int
+}
+
+type moduleParser struct {
+	modules []*Module
+	slots   map[string]*Module
+}
+
+// Parse parses the output of 'show module' (IOS, IOS XE and NX-OS) and returns the modules of the chassis
+func Parse(ostype string, output string) ([]Module, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return nil, errors.New("'show module' is not implemented for " + ostype)
+	}
+
+	p := &moduleParser{slots: make(map[string]*Module)}
+	header := ""
+	var columns []column
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r ")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			columns = nil
+			header = ""
+			continue
+		}
+		if fields[0] == "Mod" {
+			header = line
+			columns = nil
+			continue
+		}
+		if header != "" && separatorRegexp.MatchString(line) {
+			columns = parseColumns(header, line)
+			continue
+		}
+		if columns == nil {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		p.parseRow(columns, line)
+	}
+
+	modules := make([]Module, len(p.modules))
+	for i, m := range p.modules {
+		modules[i] = *m
+	}
+	return modules, nil
+}
+
+// parseColumns derives the column names and positions from a table header and its separator line
+func parseColumns(header string, separator string) []column {
+	spans := columnRegexp.FindAllStringIndex(separator, -1)
+	columns := make([]column, len(spans))
+	for i, span := range spans {
+		end := len(header)
+		if i+1 < len(spans) {
+			end = spans[i+1][0]
+		}
+		columns[i] = column{name: cell(header, span[0], end), start: span[0], end: end}
+	}
+	if len(columns) > 0 {
+		columns[len(columns)-1].end = -1
+	}
+	return columns
+}
+
+func cell(line string, start int, end int) string {
+	if start >= len(line) {
+		return ""
+	}
+	if end < 0 || end > len(line) {
+		end = len(line)
+	}
+	return strings.TrimSpace(line[start:end])
+}
+
+func (p *moduleParser) module(slot string) *Module {
+	m, ok := p.slots[slot]
+	if !ok {
+		m = &Module{Slot: slot}
+		p.slots[slot] = m
+		p.modules = append(p.modules, m)
+	}
+	return m
+}
+
+func (p *moduleParser) parseRow(columns []column, line string) {
+	values := make(map[string]string)
+	for _, c := range columns {
+		values[c.name] = cell(line, c.start, c.end)
+	}
+	slot := strings.Fields(line)[0]
+
+	// the daughter cards of 6500 and 7600 are listed by the slot of their card
+	if _, ok := values["Sub-Module"]; ok {
+		return
+	}
+	if _, ok := values["Redundancy Role"]; ok {
+		p.module(slot).Role = strings.ToLower(values["Redundancy Role"])
+		return
+	}
+	if diag, ok := values["Online Diag Status"]; ok {
+		p.module(slot).Diag = diag
+		return
+	}
+
+	m := p.module(slot)
+	for _, name := range []string{"Card Type", "Module-Type", "Module Type"} {
+		if t, ok := values[name]; ok {
+			m.Type = t
+			if matches := cardRoleRegexp.FindStringSubmatch(t); matches != nil {
+				m.Role = "standby"
+				if matches[1] == "Active" {
+					m.Role = "active"
+				}
+			}
+		}
+	}
+	if model, ok := values["Model"]; ok {
+		m.Model = model
+	}
+	if status, ok := values["Status"]; ok && status != "" {
+		// NX-OS marks the supervisor the session is connected to with '*'
+		status = strings.TrimSpace(strings.TrimSuffix(status, "*"))
+		m.Status = status
+		switch status {
+		case "active":
+			m.Role = "active"
+		case "ha-standby", "standby":
+			m.Role = "standby"
+		}
+	}
+}
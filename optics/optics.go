@@ -3,4 +3,56 @@ package optics
 type Optics struct {
 	RxPower float64
 	TxPower float64
+	Sensors []Sensor
+}
+
+// Sensor is a single digital optical monitoring reading (per lane for multi-lane optics)
+type Sensor struct {
+	Type          string
+	Lane          string
+	Value         float64
+	Flag          string
+	HighAlarm     float64
+	HighWarning   float64
+	LowWarning    float64
+	LowAlarm      float64
+	HasThresholds bool
+}
+
+const (
+	SensorTemperature = "temperature"
+	SensorVoltage     = "voltage"
+	SensorCurrent     = "current"
+	SensorTxPower     = "tx_power"
+	SensorRxPower     = "rx_power"
+)
+
+// AlarmState returns 0 if the reading is within thresholds, 1 on a warning and 2 on an alarm
+func (s Sensor) AlarmState() float64 {
+	if s.HasThresholds {
+		switch {
+		case s.Value >= s.HighAlarm || s.Value <= s.LowAlarm:
+			return 2
+		case s.Value >= s.HighWarning || s.Value <= s.LowWarning:
+			return 1
+		}
+		return 0
+	}
+	switch s.Flag {
+	case "++", "--":
+		return 2
+	case "+", "-":
+		return 1
+	}
+	return 0
+}
+
+// TransceiverInfo holds the inventory data of a transceiver, 'show inventory' of IOS and IOS XE has neither vendor nor wavelength
+type TransceiverInfo struct {
+	Type       string
+	Vendor     string
+	PartNumber string
+	Serial     string
+	Wavelength string
+	NonCisco   bool
 }
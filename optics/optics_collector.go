@@ -13,14 +13,32 @@ import (
 const prefix string = "cisco_optics_"
 
 var (
-	opticsTXDesc *prometheus.Desc
-	opticsRXDesc *prometheus.Desc
+	opticsTXDesc          *prometheus.Desc
+	opticsRXDesc          *prometheus.Desc
+	opticsTemperatureDesc *prometheus.Desc
+	opticsVoltageDesc     *prometheus.Desc
+	opticsCurrentDesc     *prometheus.Desc
+	opticsLaneTXDesc      *prometheus.Desc
+	opticsLaneRXDesc      *prometheus.Desc
+	opticsThresholdDesc   *prometheus.Desc
+	opticsAlarmDesc       *prometheus.Desc
+	opticsInfoDesc        *prometheus.Desc
+	opticsNonCiscoDesc    *prometheus.Desc
 )
 
 func init() {
 	l := []string{"target", "interface"}
 	opticsTXDesc = prometheus.NewDesc(prefix+"tx", "Transceiver Tx power", l, nil)
 	opticsRXDesc = prometheus.NewDesc(prefix+"rx", "Transceiver Rx power", l, nil)
+	opticsTemperatureDesc = prometheus.NewDesc(prefix+"temperature", "Transceiver temperature in degrees Celsius", l, nil)
+	opticsVoltageDesc = prometheus.NewDesc(prefix+"voltage", "Transceiver supply voltage in volts", l, nil)
+	opticsCurrentDesc = prometheus.NewDesc(prefix+"bias_current", "Transceiver laser bias current in milliamperes", append(l, "lane"), nil)
+	opticsLaneTXDesc = prometheus.NewDesc(prefix+"lane_tx", "Transceiver Tx power per lane", append(l, "lane"), nil)
+	opticsLaneRXDesc = prometheus.NewDesc(prefix+"lane_rx", "Transceiver Rx power per lane", append(l, "lane"), nil)
+	opticsThresholdDesc = prometheus.NewDesc(prefix+"threshold", "Alarm and warning thresholds reported by the transceiver", append(l, "sensor", "level"), nil)
+	opticsAlarmDesc = prometheus.NewDesc(prefix+"alarm", "Alarm state of a transceiver reading (0 OK, 1 warning, 2 alarm)", append(l, "sensor", "lane"), nil)
+	opticsInfoDesc = prometheus.NewDesc(prefix+"info", "Transceiver inventory information (vendor and wavelength are only known on NX-OS)", append(l, "type", "vendor", "part_number", "serial", "wavelength"), nil)
+	opticsNonCiscoDesc = prometheus.NewDesc(prefix+"non_cisco", "Transceiver is non-Cisco or flagged unsupported (1 yes, 0 no)", l, nil)
 }
 
 type opticsCollector struct {
@@ -40,6 +58,15 @@ func (*opticsCollector) Name() string {
 func (*opticsCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- opticsTXDesc
 	ch <- opticsRXDesc
+	ch <- opticsTemperatureDesc
+	ch <- opticsVoltageDesc
+	ch <- opticsCurrentDesc
+	ch <- opticsLaneTXDesc
+	ch <- opticsLaneRXDesc
+	ch <- opticsThresholdDesc
+	ch <- opticsAlarmDesc
+	ch <- opticsInfoDesc
+	ch <- opticsNonCiscoDesc
 }
 
 // Collect collects metrics from Cisco
@@ -48,12 +75,13 @@ func (c *opticsCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metri
     switch client.OSType {
     case rpc.IOS:
         iflistcmd = "show interfaces stats | exclude disabled"
-        transceiverCmd = "show interfaces transceiver"
+        transceiverCmd = "show interfaces transceiver detail"
     case rpc.NXOS:
         iflistcmd = "show interface status | exclude disabled | exclude notconn | exclude sfpAbsent | exclude --------------------------------------------------------------------------------"
         transceiverCmd = "show interface transceiver details"
     case rpc.IOSXE:
         iflistcmd = "show interfaces stats | exclude disabled"
+        transceiverCmd = "show interfaces transceiver detail"
     }
 
     out, err := client.RunCommand(iflistcmd)
@@ -68,8 +96,14 @@ func (c *opticsCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metri
         return nil
     }
 
-    if client.OSType == rpc.IOS || client.OSType == rpc.NXOS {
-        out, err = client.RunCommand(transceiverCmd)
+    out, err = client.RunCommand(transceiverCmd)
+    if client.OSType == rpc.IOSXE && (err != nil || batchUnsupported(out)) {
+        // ASR/ISR platforms do not support the batched command, query every port instead
+        if client.Debug {
+            log.Printf("Batched transceiver command not supported on %s, falling back to per-port commands\n", labelValues[0])
+        }
+        c.collectPerPort(client, ch, labelValues, interfaces)
+    } else {
         if err != nil {
             if client.Debug {
                 log.Printf("Transceiver command on %s: %s\n", labelValues[0], err.Error())
@@ -84,37 +118,130 @@ func (c *opticsCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metri
             return nil
         }
         for _, i := range interfaces {
-            if optic, ok := opticsData[i]; ok {
-                l := append(labelValues, i)
-                ch <- prometheus.MustNewConstMetric(opticsTXDesc, prometheus.GaugeValue, optic.TxPower, l...)
-                ch <- prometheus.MustNewConstMetric(opticsRXDesc, prometheus.GaugeValue, optic.RxPower, l...)
+            optic, ok := opticsData[i]
+            if !ok {
+                optic, ok = opticsData[shortInterfaceName(i)]
             }
-        }
-    } else if client.OSType == rpc.IOSXE {
-        xeDev := regexp.MustCompile(`\S(\d+)/(\d+)/(\d+)`)
-        for _, i := range interfaces {
-            matches := xeDev.FindStringSubmatch(i)
-            if matches == nil {
-                continue
+            if ok {
+                c.collectOptic(ch, append(labelValues, i), optic)
             }
-            out, err := client.RunCommand("show hw-module subslot " + matches[1] + "/" + matches[2] + " transceiver " + matches[3] + " status")
-            if err != nil {
-                if client.Debug {
-                    log.Printf("Transceiver command on %s: %s\n", labelValues[0], err.Error())
-                }
-                continue
+        }
+        if client.OSType == rpc.NXOS {
+            c.collectInfo(ch, labelValues, interfaces, parseTransceiverInfoNXOS(out))
+        }
+    }
+
+    if client.OSType == rpc.IOS || client.OSType == rpc.IOSXE {
+        out, err := client.RunCommand("show inventory")
+        if err != nil {
+            if client.Debug {
+                log.Printf("Inventory command on %s: %s\n", labelValues[0], err.Error())
             }
-            optic, err := c.ParseTransceiver(client.OSType, out)
-            if err != nil {
-                if client.Debug {
-                    log.Printf("Transceiver data for %s: %s\n", labelValues[0], err.Error())
-                }
-                continue
+            return nil
+        }
+        infos, err := c.ParseInventory(client.OSType, out)
+        if err != nil {
+            if client.Debug {
+                log.Printf("ParseInventory for %s: %s\n", labelValues[0], err.Error())
             }
-            l := append(labelValues, i)
-            ch <- prometheus.MustNewConstMetric(opticsTXDesc, prometheus.GaugeValue, optic.TxPower, l...)
-            ch <- prometheus.MustNewConstMetric(opticsRXDesc, prometheus.GaugeValue, optic.RxPower, l...)
+            return nil
         }
+        c.collectInfo(ch, labelValues, interfaces, infos)
     }
     return nil
-}
\ No newline at end of file
+}
+
+// collectPerPort runs 'show hw-module subslot X/Y transceiver Z status' for every interface (IOS XE on ASR/ISR)
+func (c *opticsCollector) collectPerPort(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string, interfaces []string) {
+	xeDev := regexp.MustCompile(`\S(\d+)/(\d+)/(\d+)`)
+	for _, i := range interfaces {
+		matches := xeDev.FindStringSubmatch(i)
+		if matches == nil {
+			continue
+		}
+		out, err := client.RunCommand("show hw-module subslot " + matches[1] + "/" + matches[2] + " transceiver " + matches[3] + " status")
+		if err != nil {
+			if client.Debug {
+				log.Printf("Transceiver command on %s: %s\n", labelValues[0], err.Error())
+			}
+			continue
+		}
+		optic, err := c.ParseTransceiver(client.OSType, out)
+		if err != nil {
+			if client.Debug {
+				log.Printf("Transceiver data for %s: %s\n", labelValues[0], err.Error())
+			}
+			continue
+		}
+		c.collectOptic(ch, append(labelValues, i), optic)
+	}
+}
+
+// collectInfo exports the inventory information of the transceivers plugged into the given interfaces
+func (c *opticsCollector) collectInfo(ch chan<- prometheus.Metric, labelValues []string, interfaces []string, infos map[string]TransceiverInfo) {
+	xeDev := regexp.MustCompile(`\S(\d+/\d+/\d+)$`)
+	for _, i := range interfaces {
+		info, ok := infos[i]
+		if !ok {
+			info, ok = infos[shortInterfaceName(i)]
+		}
+		if !ok {
+			if matches := xeDev.FindStringSubmatch(i); matches != nil {
+				info, ok = infos[matches[1]]
+			}
+		}
+		if !ok {
+			continue
+		}
+		l := append(labelValues, i)
+		ch <- prometheus.MustNewConstMetric(opticsInfoDesc, prometheus.GaugeValue, 1, append(l, info.Type, info.Vendor, info.PartNumber, info.Serial, info.Wavelength)...)
+		nonCisco := 0.0
+		if info.NonCisco {
+			nonCisco = 1
+		}
+		ch <- prometheus.MustNewConstMetric(opticsNonCiscoDesc, prometheus.GaugeValue, nonCisco, l...)
+	}
+}
+
+// collectOptic exports the power levels, DOM readings, thresholds and alarm states of one transceiver
+func (c *opticsCollector) collectOptic(ch chan<- prometheus.Metric, l []string, optic Optics) {
+	ch <- prometheus.MustNewConstMetric(opticsTXDesc, prometheus.GaugeValue, optic.TxPower, l...)
+	ch <- prometheus.MustNewConstMetric(opticsRXDesc, prometheus.GaugeValue, optic.RxPower, l...)
+
+	multiLane := false
+	for _, s := range optic.Sensors {
+		if s.Lane != "" && s.Lane != "1" {
+			multiLane = true
+		}
+	}
+
+	thresholds := make(map[string]bool)
+	for _, s := range optic.Sensors {
+		switch s.Type {
+		case SensorTemperature:
+			ch <- prometheus.MustNewConstMetric(opticsTemperatureDesc, prometheus.GaugeValue, s.Value, l...)
+		case SensorVoltage:
+			ch <- prometheus.MustNewConstMetric(opticsVoltageDesc, prometheus.GaugeValue, s.Value, l...)
+		case SensorCurrent:
+			ch <- prometheus.MustNewConstMetric(opticsCurrentDesc, prometheus.GaugeValue, s.Value, append(l, s.Lane)...)
+		case SensorTxPower:
+			if multiLane {
+				ch <- prometheus.MustNewConstMetric(opticsLaneTXDesc, prometheus.GaugeValue, s.Value, append(l, s.Lane)...)
+			}
+		case SensorRxPower:
+			if multiLane {
+				ch <- prometheus.MustNewConstMetric(opticsLaneRXDesc, prometheus.GaugeValue, s.Value, append(l, s.Lane)...)
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(opticsAlarmDesc, prometheus.GaugeValue, s.AlarmState(), append(l, s.Type, s.Lane)...)
+
+		if !s.HasThresholds || thresholds[s.Type] {
+			continue
+		}
+		thresholds[s.Type] = true
+		ch <- prometheus.MustNewConstMetric(opticsThresholdDesc, prometheus.GaugeValue, s.HighAlarm, append(l, s.Type, "high_alarm")...)
+		ch <- prometheus.MustNewConstMetric(opticsThresholdDesc, prometheus.GaugeValue, s.HighWarning, append(l, s.Type, "high_warning")...)
+		ch <- prometheus.MustNewConstMetric(opticsThresholdDesc, prometheus.GaugeValue, s.LowWarning, append(l, s.Type, "low_warning")...)
+		ch <- prometheus.MustNewConstMetric(opticsThresholdDesc, prometheus.GaugeValue, s.LowAlarm, append(l, s.Type, "low_alarm")...)
+	}
+}
@@ -9,6 +9,29 @@ import (
 	"github.com/moeinshahcheraghi/cisco_exporter/util"
 )
 
+var (
+	flagRegexp          = regexp.MustCompile(`^(?:\+\+|\+|--|-)$`)
+	numberRegexp        = regexp.MustCompile(`^-?\d+(?:\.\d+)?$`)
+	portRegexp          = regexp.MustCompile(`^[A-Za-z][A-Za-z-]*\d[\w/.:]*$`)
+	nxosInterfaceRegexp = regexp.MustCompile(`^(Ethernet\S+)\s*$`)
+	nxosLaneRegexp      = regexp.MustCompile(`^\s*Lane Number:\s*(\d+)`)
+	nxosSensorRegexp    = regexp.MustCompile(`^\s*(Temperature|Voltage|Current|Tx Power|Rx Power)\s+(.*)$`)
+	nxosUnits           = map[string]bool{"C": true, "V": true, "mA": true, "dBm": true}
+	interfacePrefixes   = []struct{ long, short string }{
+		{"TwentyFiveGigE", "Twe"},
+		{"TwoGigabitEthernet", "Tw"},
+		{"FiveGigabitEthernet", "Fi"},
+		{"TenGigabitEthernet", "Te"},
+		{"FortyGigabitEthernet", "Fo"},
+		{"HundredGigE", "Hu"},
+		{"FourHundredGigE", "FH"},
+		{"AppGigabitEthernet", "Ap"},
+		{"GigabitEthernet", "Gi"},
+		{"FastEthernet", "Fa"},
+		{"Ethernet", "Et"},
+	}
+)
+
 // ParseInterfaces parses cli output and returns list of interface names
 func (c *opticsCollector) ParseInterfaces(ostype string, output string) ([]string, error) {
 	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
@@ -41,51 +64,354 @@ func (c *opticsCollector) ParseTransceiver(ostype string, output string) (Optics
 	if matches == nil {
 		return Optics{}, errors.New("Transceiver not found")
 	}
-	return Optics{
+	optic := Optics{
 		TxPower: util.Str2float64(matches[1]),
 		RxPower: util.Str2float64(matches[2]),
-	}, nil
+	}
+	if ostype == rpc.IOSXE {
+		optic.Sensors = parseHWModuleSensors(output)
+	}
+	return optic, nil
 }
 
+// parseHWModuleSensors parses the readings of 'show hw-module subslot X/Y transceiver Z status' (IOS XE)
+func parseHWModuleSensors(output string) []Sensor {
+	sensorRegexp := regexp.MustCompile(`^\s*(Module temperature|Transceiver Tx supply voltage|Transceiver Tx bias current|Transceiver Tx power|Transceiver Rx optical power)\s+= \+?(-?\d+(?:\.\d+)?) (\S+)`)
+	sensors := []Sensor{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := sensorRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		value := util.Str2float64(matches[2])
+		s := Sensor{Value: value}
+		switch matches[1] {
+		case "Module temperature":
+			s.Type = SensorTemperature
+		case "Transceiver Tx supply voltage":
+			s.Type = SensorVoltage
+			if matches[3] == "mVolts" {
+				s.Value = value / 1000
+			}
+		case "Transceiver Tx bias current":
+			s.Type = SensorCurrent
+			if matches[3] == "uAmps" {
+				s.Value = value / 1000
+			}
+		case "Transceiver Tx power":
+			s.Type = SensorTxPower
+		case "Transceiver Rx optical power":
+			s.Type = SensorRxPower
+		}
+		sensors = append(sensors, s)
+	}
+	return sensors
+}
 
+// ParseAllTransceivers parses the transceiver details of all interfaces ('show interfaces transceiver detail'
+// on IOS and IOS XE Catalyst, 'show interface transceiver details' on NX-OS) and returns the DOM readings keyed by interface name
 func (c *opticsCollector) ParseAllTransceivers(ostype string, output string) (map[string]Optics, error) {
-    items := make(map[string]Optics)
-    if ostype == rpc.IOS {
-        lines := strings.Split(output, "\n")
-        re := regexp.MustCompile(`(\S+)\s+((?:-)?\d+\.\d+)\s+((?:-)?\d+\.\d+)`)
-        for _, line := range lines {
-            if strings.HasPrefix(line, "Interface") || strings.HasPrefix(line, "----------") {
-                continue
-            }
-            matches := re.FindStringSubmatch(line)
-            if matches != nil {
-                items[matches[1]] = Optics{
-                    RxPower: util.Str2float64(matches[2]),
-                    TxPower: util.Str2float64(matches[3]),
-                }
-            }
-        }
-    } else if ostype == rpc.NXOS {
-        sections := strings.Split(output, "\n\n")
-        reTx := regexp.MustCompile(`Tx Power\s*((?:-)?\d+\.\d+)`)
-        reRx := regexp.MustCompile(`Rx Power\s*((?:-)?\d+\.\d+)`)
-        for _, section := range sections {
-            lines := strings.Split(section, "\n")
-            if len(lines) > 0 && strings.HasPrefix(lines[0], "Ethernet") {
-                iface := strings.TrimSpace(lines[0])
-                var tx, rx float64
-                for _, line := range lines {
-                    if matches := reTx.FindStringSubmatch(line); matches != nil {
-                        tx = util.Str2float64(matches[1])
-                    } else if matches := reRx.FindStringSubmatch(line); matches != nil {
-                        rx = util.Str2float64(matches[1])
-                    }
-                }
-                items[iface] = Optics{RxPower: rx, TxPower: tx}
-            }
-        }
-    } else {
-        return nil, errors.New("Unsupported OS type for batch parsing")
-    }
-    return items, nil
-}
\ No newline at end of file
+	switch ostype {
+	case rpc.IOS, rpc.IOSXE:
+		return parseTransceiverDetail(output), nil
+	case rpc.NXOS:
+		return parseTransceiverDetailsNXOS(output), nil
+	}
+	return nil, errors.New("Unsupported OS type for batch parsing")
+}
+
+// batchUnsupported reports whether the device rejected the batched transceiver command
+func batchUnsupported(output string) bool {
+	return strings.Contains(output, "% Invalid input") || strings.Contains(output, "% Incomplete command")
+}
+
+// parseTransceiverDetail parses the threshold tables of 'show interfaces transceiver detail' (IOS, IOS XE)
+func parseTransceiverDetail(output string) map[string]Optics {
+	items := make(map[string]Optics)
+	sensorType := ""
+	hasLane := false
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if strings.Contains(line, "Threshold") {
+			switch {
+			case strings.Contains(line, "Temperature"):
+				sensorType = SensorTemperature
+			case strings.Contains(line, "Voltage"):
+				sensorType = SensorVoltage
+			case strings.Contains(line, "Current"):
+				sensorType = SensorCurrent
+			case strings.Contains(line, "Transmit Power"):
+				sensorType = SensorTxPower
+			case strings.Contains(line, "Receive Power"):
+				sensorType = SensorRxPower
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "Port" {
+			hasLane = len(fields) > 1 && fields[1] == "Lane"
+			continue
+		}
+		if sensorType == "" || len(fields) < 2 || !portRegexp.MatchString(fields[0]) {
+			continue
+		}
+
+		s := Sensor{Type: sensorType}
+		values := fields[1:]
+		if hasLane {
+			s.Lane = values[0]
+			values = values[1:]
+		}
+		if len(values) == 0 || !numberRegexp.MatchString(values[0]) {
+			continue
+		}
+		s.Value = util.Str2float64(values[0])
+		values = values[1:]
+		if len(values) > 0 && flagRegexp.MatchString(values[0]) {
+			s.Flag = values[0]
+			values = values[1:]
+		}
+		if len(values) >= 4 && allNumbers(values[:4]) {
+			s.HighAlarm = util.Str2float64(values[0])
+			s.HighWarning = util.Str2float64(values[1])
+			s.LowWarning = util.Str2float64(values[2])
+			s.LowAlarm = util.Str2float64(values[3])
+			s.HasThresholds = true
+		}
+		items[fields[0]] = addSensor(items[fields[0]], s)
+	}
+	return items
+}
+
+// parseTransceiverDetailsNXOS parses 'show interface transceiver details' (NX-OS)
+func parseTransceiverDetailsNXOS(output string) map[string]Optics {
+	items := make(map[string]Optics)
+	iface := ""
+	lane := ""
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if matches := nxosInterfaceRegexp.FindStringSubmatch(line); matches != nil {
+			iface = matches[1]
+			lane = ""
+			continue
+		}
+		if iface == "" {
+			continue
+		}
+		if matches := nxosLaneRegexp.FindStringSubmatch(line); matches != nil {
+			lane = matches[1]
+			continue
+		}
+		matches := nxosSensorRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		values := []string{}
+		flag := ""
+		for _, f := range strings.Fields(matches[2]) {
+			if nxosUnits[f] {
+				continue
+			}
+			if flagRegexp.MatchString(f) && len(values) == 1 {
+				flag = f
+				continue
+			}
+			values = append(values, f)
+		}
+		if len(values) == 0 || !numberRegexp.MatchString(values[0]) {
+			continue
+		}
+
+		s := Sensor{
+			Lane:  lane,
+			Value: util.Str2float64(values[0]),
+			Flag:  flag,
+		}
+		switch matches[1] {
+		case "Temperature":
+			s.Type = SensorTemperature
+		case "Voltage":
+			s.Type = SensorVoltage
+		case "Current":
+			s.Type = SensorCurrent
+		case "Tx Power":
+			s.Type = SensorTxPower
+		case "Rx Power":
+			s.Type = SensorRxPower
+		}
+		if len(values) >= 5 && allNumbers(values[1:5]) {
+			s.HighAlarm = util.Str2float64(values[1])
+			s.LowAlarm = util.Str2float64(values[2])
+			s.HighWarning = util.Str2float64(values[3])
+			s.LowWarning = util.Str2float64(values[4])
+			s.HasThresholds = true
+		}
+		items[iface] = addSensor(items[iface], s)
+	}
+	return items
+}
+
+// addSensor adds a reading to the optic, the first lane also provides the interface level Tx/Rx power
+func addSensor(optic Optics, s Sensor) Optics {
+	if s.Lane != "" && (s.Type == SensorTemperature || s.Type == SensorVoltage) {
+		for _, existing := range optic.Sensors {
+			if existing.Type == s.Type {
+				return optic
+			}
+		}
+		s.Lane = ""
+	}
+	if s.Lane == "" || s.Lane == "1" {
+		switch s.Type {
+		case SensorTxPower:
+			optic.TxPower = s.Value
+		case SensorRxPower:
+			optic.RxPower = s.Value
+		}
+	}
+	optic.Sensors = append(optic.Sensors, s)
+	return optic
+}
+
+// shortInterfaceName returns the abbreviated interface name used in transceiver tables (GigabitEthernet1/0/1 -> Gi1/0/1)
+func shortInterfaceName(name string) string {
+	for _, p := range interfacePrefixes {
+		if strings.HasPrefix(name, p.long) {
+			return p.short + strings.TrimPrefix(name, p.long)
+		}
+	}
+	return name
+}
+
+func allNumbers(values []string) bool {
+	for _, v := range values {
+		if !numberRegexp.MatchString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseInventory parses the output of 'show inventory' (IOS, IOS XE) and returns the transceivers keyed by
+// inventory name (the interface name on Catalyst, "X/Y/Z" for 'subslot X/Y transceiver Z' entries)
+func (c *opticsCollector) ParseInventory(ostype string, output string) (map[string]TransceiverInfo, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.IOS {
+		return nil, errors.New("'show inventory' is not implemented for " + ostype)
+	}
+	nameRegexp := regexp.MustCompile(`^\s*NAME:\s*"([^"]*)",\s*DESCR:\s*"([^"]*)"`)
+	pidRegexp := regexp.MustCompile(`^\s*PID:\s*([^,]*?)\s*,\s*VID:\s*([^,]*?)\s*,\s*SN:\s*(\S*)`)
+	subslotRegexp := regexp.MustCompile(`subslot (\d+)/(\d+) transceiver (\d+)`)
+
+	items := make(map[string]TransceiverInfo)
+	name := ""
+	descr := ""
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if matches := nameRegexp.FindStringSubmatch(line); matches != nil {
+			name = matches[1]
+			descr = matches[2]
+			if m := subslotRegexp.FindStringSubmatch(name); m != nil {
+				name = m[1] + "/" + m[2] + "/" + m[3]
+			}
+			continue
+		}
+		matches := pidRegexp.FindStringSubmatch(line)
+		if matches == nil || name == "" {
+			continue
+		}
+		if !portRegexp.MatchString(name) && !strings.Contains(name, "/") {
+			name = ""
+			continue
+		}
+		if !isTransceiverDescr(descr) {
+			name = ""
+			continue
+		}
+		pid := matches[1]
+		items[name] = TransceiverInfo{
+			Type:       descr,
+			PartNumber: pid,
+			Serial:     matches[3],
+			NonCisco:   pid == "" || pid == "N/A" || strings.EqualFold(pid, "Unspecified") || strings.Contains(strings.ToLower(descr), "unsupported"),
+		}
+		name = ""
+	}
+	return items, nil
+}
+
+// isTransceiverDescr reports whether an inventory description belongs to a pluggable optic
+func isTransceiverDescr(descr string) bool {
+	d := strings.ToUpper(descr)
+	for _, s := range []string{"SFP", "XFP", "QSFP", "CFP", "GBIC", "X2", "BASE", "TRANSCEIVER", "UNSUPPORTED"} {
+		if strings.Contains(d, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTransceiverInfoNXOS parses the inventory part of 'show interface transceiver details' (NX-OS)
+func parseTransceiverInfoNXOS(output string) map[string]TransceiverInfo {
+	attrRegexp := regexp.MustCompile(`^\s+(type|name|part number|serial number|cisco product id) is (.*?)\s*$`)
+	wavelengthRegexp := regexp.MustCompile(`wavelength (?:is )?(\d+(?:\.\d+)?) ?nm`)
+
+	items := make(map[string]TransceiverInfo)
+	iface := ""
+	var info TransceiverInfo
+	ciscoPID := ""
+	flush := func() {
+		if iface == "" || info == (TransceiverInfo{}) {
+			return
+		}
+		if ciscoPID != "" {
+			info.PartNumber = ciscoPID
+		}
+		if !strings.Contains(strings.ToUpper(info.Vendor), "CISCO") && ciscoPID == "" {
+			info.NonCisco = true
+		}
+		items[iface] = info
+	}
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if matches := nxosInterfaceRegexp.FindStringSubmatch(line); matches != nil {
+			flush()
+			iface = matches[1]
+			info = TransceiverInfo{}
+			ciscoPID = ""
+			continue
+		}
+		if iface == "" {
+			continue
+		}
+		if strings.Contains(line, "not supported") || strings.Contains(line, "unsupported") {
+			info.NonCisco = true
+		}
+		if matches := wavelengthRegexp.FindStringSubmatch(line); matches != nil {
+			info.Wavelength = matches[1]
+			continue
+		}
+		matches := attrRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		switch matches[1] {
+		case "type":
+			info.Type = matches[2]
+		case "name":
+			info.Vendor = matches[2]
+		case "part number":
+			info.PartNumber = matches[2]
+		case "serial number":
+			info.Serial = matches[2]
+		case "cisco product id":
+			ciscoPID = matches[2]
+		}
+	}
+	flush()
+	return items
+}
@@ -0,0 +1,155 @@
+package qos
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/moeinshahcheraghi/cisco_exporter/util"
+)
+
+var (
+	interfaceRegexp     = regexp.MustCompile(`^\s?([A-Za-z][A-Za-z-]*\d[\w/.:-]*)\s*$`)
+	servicePolicyRegexp = regexp.MustCompile(`^(\s*)Service-policy\s*(?:\(\S+\)\s*)?(input|output)?\s*:\s*(\S+)`)
+	classMapRegexp      = regexp.MustCompile(`^(\s*)Class-map\s*(?:\(\S+\)\s*)?:\s*(\S+)`)
+	matchRegexp         = regexp.MustCompile(`^\s*Match:`)
+	aggregateRegexp     = regexp.MustCompile(`^\s*Aggregate forwarded`)
+	packetsRegexp       = regexp.MustCompile(`^\s*(\d+) packets(?:, (\d+) bytes)?\s*$`)
+	rateRegexp          = regexp.MustCompile(`\d+ (?:second|minute) offered rate (\d+) bps(?:, drop rate (\d+) bps)?`)
+	queueStatsRegexp    = regexp.MustCompile(`\((?:queue )?depth/total drops/no-buffer drops[^)]*\) (\d+)/(\d+)/(\d+)`)
+	totalDropsRegexp    = regexp.MustCompile(`^\s*\(total drops\) (\d+)`)
+	nxosDropsRegexp     = regexp.MustCompile(`^\s*queue dropped pkts\s*:\s*(\d+)`)
+	policeRegexp        = regexp.MustCompile(`^\s*(conformed|exceeded|violated) (\d+) packets, (\d+) bytes`)
+	wredHeaderRegexp    = regexp.MustCompile(`Random drop\s+Tail drop`)
+	wredRowRegexp       = regexp.MustCompile(`^\s*(\S+)\s+\d+/\d+\s+(\d+)/(\d+)\s+(\d+)/(\d+)`)
+)
+
+type policyLevel struct {
+	indent int
+	policy string
+	class  *ClassStats
+}
+
+// Parse parses the output of 'show policy-map interface' and returns the counters of every class-map,
+// keeping track of the interface -> direction -> service-policy -> class-map hierarchy
+func Parse(ostype string, output string) ([]ClassStats, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return nil, errors.New("'show policy-map interface' is not implemented for " + ostype)
+	}
+
+	items := []*ClassStats{}
+	levels := []policyLevel{}
+	var current *ClassStats
+	var iface, direction string
+	matched, inMatch, inWRED := false, false, false
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if matches := interfaceRegexp.FindStringSubmatch(line); matches != nil {
+			iface = matches[1]
+			direction = ""
+			levels = levels[:0]
+			current = nil
+			continue
+		}
+		if matches := servicePolicyRegexp.FindStringSubmatch(line); matches != nil {
+			indent := len(matches[1])
+			for len(levels) > 0 && levels[len(levels)-1].indent >= indent {
+				levels = levels[:len(levels)-1]
+			}
+			if matches[2] != "" {
+				direction = matches[2]
+			}
+			levels = append(levels, policyLevel{indent: indent, policy: matches[3]})
+			current = nil
+			continue
+		}
+		if matches := classMapRegexp.FindStringSubmatch(line); matches != nil {
+			if iface == "" || len(levels) == 0 {
+				continue
+			}
+			indent := len(matches[1])
+			for len(levels) > 1 && levels[len(levels)-1].indent >= indent {
+				levels = levels[:len(levels)-1]
+			}
+			parent := ""
+			if len(levels) > 1 && levels[len(levels)-2].class != nil {
+				parent = levels[len(levels)-2].class.Class
+			}
+			current = &ClassStats{
+				Interface:   iface,
+				Direction:   direction,
+				Policy:      levels[len(levels)-1].policy,
+				ParentClass: parent,
+				Class:       matches[2],
+			}
+			levels[len(levels)-1].class = current
+			items = append(items, current)
+			matched, inMatch, inWRED = false, false, false
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if matchRegexp.MatchString(line) {
+			inMatch = true
+		} else if aggregateRegexp.MatchString(line) {
+			matched = false
+		} else if matches := packetsRegexp.FindStringSubmatch(line); matches != nil {
+			if !matched && !inMatch {
+				current.MatchedPackets = util.Str2float64(matches[1])
+				if matches[2] != "" {
+					current.MatchedBytes = util.Str2float64(matches[2])
+				}
+				matched = true
+			}
+		} else if matches := rateRegexp.FindStringSubmatch(line); matches != nil {
+			current.OfferedRate = util.Str2float64(matches[1])
+			if matches[2] != "" {
+				current.DropRate = util.Str2float64(matches[2])
+			}
+		} else if matches := queueStatsRegexp.FindStringSubmatch(line); matches != nil {
+			current.HasQueueing = true
+			current.QueueDepth = util.Str2float64(matches[1])
+			current.TotalDrops = util.Str2float64(matches[2])
+			current.NoBufferDrops = util.Str2float64(matches[3])
+		} else if matches := totalDropsRegexp.FindStringSubmatch(line); matches != nil {
+			current.HasQueueing = true
+			current.TotalDrops = util.Str2float64(matches[1])
+		} else if matches := nxosDropsRegexp.FindStringSubmatch(line); matches != nil {
+			current.HasQueueing = true
+			current.TotalDrops = util.Str2float64(matches[1])
+		} else if matches := policeRegexp.FindStringSubmatch(line); matches != nil {
+			current.addPolice(matches[1], util.Str2float64(matches[2]), util.Str2float64(matches[3]))
+		} else if wredHeaderRegexp.MatchString(line) {
+			inWRED = true
+		} else if matches := wredRowRegexp.FindStringSubmatch(line); matches != nil && inWRED {
+			current.WRED = append(current.WRED, WREDStats{
+				Class:           matches[1],
+				RandomDropPkts:  util.Str2float64(matches[2]),
+				RandomDropBytes: util.Str2float64(matches[3]),
+				TailDropPkts:    util.Str2float64(matches[4]),
+				TailDropBytes:   util.Str2float64(matches[5]),
+			})
+		}
+	}
+
+	result := make([]ClassStats, len(items))
+	for i, item := range items {
+		result[i] = *item
+	}
+	return result, nil
+}
+
+func (s *ClassStats) addPolice(action string, packets, bytes float64) {
+	for i := range s.Police {
+		if s.Police[i].Action == action {
+			s.Police[i].Packets += packets
+			s.Police[i].Bytes += bytes
+			return
+		}
+	}
+	s.Police = append(s.Police, PoliceStats{Action: action, Packets: packets, Bytes: bytes})
+}
@@ -0,0 +1,39 @@
+package qos
+
+// ClassStats holds the counters of one class-map inside a service-policy
+type ClassStats struct {
+	Interface   string
+	Direction   string
+	Policy      string
+	ParentClass string
+	Class       string
+
+	MatchedPackets float64
+	MatchedBytes   float64
+	OfferedRate    float64
+	DropRate       float64
+
+	QueueDepth    float64
+	TotalDrops    float64
+	NoBufferDrops float64
+	HasQueueing   bool
+
+	Police []PoliceStats
+	WRED   []WREDStats
+}
+
+// PoliceStats holds the counters of one policer action (conformed, exceeded, violated)
+type PoliceStats struct {
+	Action  string
+	Packets float64
+	Bytes   float64
+}
+
+// WREDStats holds the drop counters of one WRED class (precedence/dscp value)
+type WREDStats struct {
+	Class           string
+	RandomDropPkts  float64
+	RandomDropBytes float64
+	TailDropPkts    float64
+	TailDropBytes   float64
+}
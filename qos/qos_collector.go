@@ -1,74 +1,111 @@
 package qos
 
 import (
-    "regexp"
-    "strings"
-    "strconv"
-    "github.com/moeinshahcheraghi/cisco_exporter/collector"
-    "github.com/moeinshahcheraghi/cisco_exporter/rpc"
-    "github.com/prometheus/client_golang/prometheus"
+	"log"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const prefix = "cisco_qos_"
 
 var (
-    dropsDesc *prometheus.Desc
+	matchedPacketsDesc *prometheus.Desc
+	matchedBytesDesc   *prometheus.Desc
+	offeredRateDesc    *prometheus.Desc
+	dropRateDesc       *prometheus.Desc
+	queueDepthDesc     *prometheus.Desc
+	dropsDesc          *prometheus.Desc
+	noBufferDropsDesc  *prometheus.Desc
+	policePacketsDesc  *prometheus.Desc
+	policeBytesDesc    *prometheus.Desc
+	wredPacketsDesc    *prometheus.Desc
+	wredBytesDesc      *prometheus.Desc
 )
 
 func init() {
-    dropsDesc = prometheus.NewDesc(prefix+"drops", "Number of QoS drops per interface and queue", []string{"target", "interface", "queue"}, nil)
+	l := []string{"target", "interface", "direction", "policy", "parent_class", "class"}
+	matchedPacketsDesc = prometheus.NewDesc(prefix+"class_matched_packets", "Number of packets matched by the class-map", l, nil)
+	matchedBytesDesc = prometheus.NewDesc(prefix+"class_matched_bytes", "Number of bytes matched by the class-map", l, nil)
+	offeredRateDesc = prometheus.NewDesc(prefix+"class_offered_rate_bps", "Offered rate of the class-map in bits per second", l, nil)
+	dropRateDesc = prometheus.NewDesc(prefix+"class_drop_rate_bps", "Drop rate of the class-map in bits per second", l, nil)
+	queueDepthDesc = prometheus.NewDesc(prefix+"queue_depth", "Current queue depth of the class-map in packets", l, nil)
+	dropsDesc = prometheus.NewDesc(prefix+"drops", "Number of packets dropped by the class-map queue", l, nil)
+	noBufferDropsDesc = prometheus.NewDesc(prefix+"no_buffer_drops", "Number of packets dropped because of missing buffers", l, nil)
+	policePacketsDesc = prometheus.NewDesc(prefix+"police_packets", "Number of packets handled by the policer per action", append(l, "action"), nil)
+	policeBytesDesc = prometheus.NewDesc(prefix+"police_bytes", "Number of bytes handled by the policer per action", append(l, "action"), nil)
+	wredPacketsDesc = prometheus.NewDesc(prefix+"wred_drop_packets", "Number of packets dropped by WRED per WRED class (random or tail drop)", append(l, "wred_class", "type"), nil)
+	wredBytesDesc = prometheus.NewDesc(prefix+"wred_drop_bytes", "Number of bytes dropped by WRED per WRED class (random or tail drop)", append(l, "wred_class", "type"), nil)
 }
 
 type qosCollector struct{}
 
+// NewCollector creates a new collector
 func NewCollector() collector.RPCCollector {
-    return &qosCollector{}
+	return &qosCollector{}
 }
 
+// Name returns the name of the collector
 func (*qosCollector) Name() string {
-    return "QoS"
+	return "QoS"
 }
 
+// Describe describes the metrics
 func (*qosCollector) Describe(ch chan<- *prometheus.Desc) {
-    ch <- dropsDesc
+	ch <- matchedPacketsDesc
+	ch <- matchedBytesDesc
+	ch <- offeredRateDesc
+	ch <- dropRateDesc
+	ch <- queueDepthDesc
+	ch <- dropsDesc
+	ch <- noBufferDropsDesc
+	ch <- policePacketsDesc
+	ch <- policeBytesDesc
+	ch <- wredPacketsDesc
+	ch <- wredBytesDesc
 }
 
+// Collect collects metrics from Cisco
 func (c *qosCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
-    out, err := client.RunCommand("show policy-map interface")
-    if err != nil {
-        return err
-    }
-    drops, err := parseQoSDrops(out)
-    if err != nil {
-        return err
-    }
-    for _, drop := range drops {
-        labels := append(labelValues, drop.Interface, drop.Queue)
-        ch <- prometheus.MustNewConstMetric(dropsDesc, prometheus.CounterValue, float64(drop.Count), labels...)
-    }
-    return nil
-}
+	out, err := client.RunCommand("show policy-map interface")
+	if err != nil {
+		return err
+	}
+	items, err := Parse(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("Parse policy-map interface for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
 
-type Drop struct {
-    Interface string
-    Queue     string
-    Count     int
-}
+	for _, item := range items {
+		l := append(labelValues, item.Interface, item.Direction, item.Policy, item.ParentClass, item.Class)
+
+		ch <- prometheus.MustNewConstMetric(matchedPacketsDesc, prometheus.CounterValue, item.MatchedPackets, l...)
+		ch <- prometheus.MustNewConstMetric(matchedBytesDesc, prometheus.CounterValue, item.MatchedBytes, l...)
+		ch <- prometheus.MustNewConstMetric(offeredRateDesc, prometheus.GaugeValue, item.OfferedRate, l...)
+		ch <- prometheus.MustNewConstMetric(dropRateDesc, prometheus.GaugeValue, item.DropRate, l...)
+
+		if item.HasQueueing {
+			ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, item.QueueDepth, l...)
+			ch <- prometheus.MustNewConstMetric(dropsDesc, prometheus.CounterValue, item.TotalDrops, l...)
+			ch <- prometheus.MustNewConstMetric(noBufferDropsDesc, prometheus.CounterValue, item.NoBufferDrops, l...)
+		}
+
+		for _, p := range item.Police {
+			ch <- prometheus.MustNewConstMetric(policePacketsDesc, prometheus.CounterValue, p.Packets, append(l, p.Action)...)
+			ch <- prometheus.MustNewConstMetric(policeBytesDesc, prometheus.CounterValue, p.Bytes, append(l, p.Action)...)
+		}
+
+		for _, w := range item.WRED {
+			ch <- prometheus.MustNewConstMetric(wredPacketsDesc, prometheus.CounterValue, w.RandomDropPkts, append(l, w.Class, "random")...)
+			ch <- prometheus.MustNewConstMetric(wredPacketsDesc, prometheus.CounterValue, w.TailDropPkts, append(l, w.Class, "tail")...)
+			ch <- prometheus.MustNewConstMetric(wredBytesDesc, prometheus.CounterValue, w.RandomDropBytes, append(l, w.Class, "random")...)
+			ch <- prometheus.MustNewConstMetric(wredBytesDesc, prometheus.CounterValue, w.TailDropBytes, append(l, w.Class, "tail")...)
+		}
+	}
 
-func parseQoSDrops(output string) ([]Drop, error) {
-    var drops []Drop
-    lines := strings.Split(output, "\n")
-    re := regexp.MustCompile(`(\S+)\s+queue\s+(\S+):\s+(\d+)\s+drops`)
-    for _, line := range lines {
-        matches := re.FindStringSubmatch(line)
-        if matches != nil {
-            count, _ := strconv.Atoi(matches[3])
-            drops = append(drops, Drop{
-                Interface: matches[1],
-                Queue:     matches[2],
-                Count:     count,
-            })
-        }
-    }
-    return drops, nil
+	return nil
 }
@@ -0,0 +1,10 @@
+package qos
+
+// QueueCounter is a single hardware queue counter of an interface
+type QueueCounter struct {
+	Interface string
+	Queue     string
+	Threshold string
+	Type      string
+	Value     float64
+}
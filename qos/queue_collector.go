@@ -0,0 +1,127 @@
+package qos
+
+import (
+	"log"
+	"sort"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	queueBuffersDesc        *prometheus.Desc
+	queueEnqueueBytesDesc   *prometheus.Desc
+	queueDropBytesDesc      *prometheus.Desc
+	queueOtherDropBytesDesc *prometheus.Desc
+	queueEnqueuePacketsDesc *prometheus.Desc
+	queueDropPacketsDesc    *prometheus.Desc
+)
+
+func init() {
+	l := []string{"target", "interface", "queue"}
+	queueBuffersDesc = prometheus.NewDesc(prefix+"hw_queue_buffers", "Number of buffers allocated to the hardware queue", l, nil)
+	queueOtherDropBytesDesc = prometheus.NewDesc(prefix+"hw_queue_other_drop_bytes", "Bytes dropped by the hardware queue for reasons other than a threshold (sbuf, qeb, qpolicer)", append(l, "type"), nil)
+	l = append(l, "threshold")
+	queueEnqueueBytesDesc = prometheus.NewDesc(prefix+"hw_queue_enqueue_bytes", "Bytes enqueued to the hardware queue per threshold", l, nil)
+	queueDropBytesDesc = prometheus.NewDesc(prefix+"hw_queue_drop_bytes", "Bytes dropped by the hardware queue per threshold", l, nil)
+	queueEnqueuePacketsDesc = prometheus.NewDesc(prefix+"hw_queue_enqueue_packets", "Packets enqueued to the output queue per threshold", l, nil)
+	queueDropPacketsDesc = prometheus.NewDesc(prefix+"hw_queue_drop_packets", "Packets dropped by the output queue per threshold", l, nil)
+}
+
+type queueCollector struct{}
+
+// NewQueueCollector creates a new collector for hardware queue statistics
+func NewQueueCollector() collector.RPCCollector {
+	return &queueCollector{}
+}
+
+// Name returns the name of the collector
+func (*queueCollector) Name() string {
+	return "QoSQueues"
+}
+
+// Describe describes the metrics
+func (*queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueBuffersDesc
+	ch <- queueEnqueueBytesDesc
+	ch <- queueDropBytesDesc
+	ch <- queueOtherDropBytesDesc
+	ch <- queueEnqueuePacketsDesc
+	ch <- queueDropPacketsDesc
+}
+
+// Collect collects metrics from Cisco
+func (c *queueCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	var items []QueueCounter
+	switch client.OSType {
+	case rpc.IOS:
+		out, err := client.RunCommand("show mls qos interface statistics")
+		if err != nil {
+			return err
+		}
+		items, err = ParseMLSQueueStats(client.OSType, out)
+		if err != nil {
+			if client.Debug {
+				log.Printf("ParseMLSQueueStats for %s: %s\n", labelValues[0], err.Error())
+			}
+			return nil
+		}
+	case rpc.IOSXE:
+		out, err := client.RunCommand("show ip interface brief | include up")
+		if err != nil {
+			return err
+		}
+		interfaces, err := ParseUpInterfaces(client.OSType, out)
+		if err != nil {
+			if client.Debug {
+				log.Printf("ParseUpInterfaces for %s: %s\n", labelValues[0], err.Error())
+			}
+			return nil
+		}
+		names := make([]string, 0, len(interfaces))
+		for name := range interfaces {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			out, err := client.RunCommand("show platform hardware fed switch " + interfaces[name] + " qos queue stats interface " + name)
+			if err != nil {
+				if client.Debug {
+					log.Printf("Queue stats command on %s: %s\n", labelValues[0], err.Error())
+				}
+				continue
+			}
+			counters, err := ParseQueueStats(client.OSType, name, out)
+			if err != nil {
+				if client.Debug {
+					log.Printf("ParseQueueStats for %s: %s\n", labelValues[0], err.Error())
+				}
+				continue
+			}
+			items = append(items, counters...)
+		}
+	default:
+		return nil
+	}
+
+	for _, item := range items {
+		l := append(labelValues, item.Interface, item.Queue)
+		switch {
+		case item.Type == "buffers":
+			ch <- prometheus.MustNewConstMetric(queueBuffersDesc, prometheus.GaugeValue, item.Value, l...)
+		case item.Type == "enqueue" && client.OSType == rpc.IOS:
+			ch <- prometheus.MustNewConstMetric(queueEnqueuePacketsDesc, prometheus.CounterValue, item.Value, append(l, item.Threshold)...)
+		case item.Type == "drop" && client.OSType == rpc.IOS:
+			ch <- prometheus.MustNewConstMetric(queueDropPacketsDesc, prometheus.CounterValue, item.Value, append(l, item.Threshold)...)
+		case item.Type == "enqueue":
+			ch <- prometheus.MustNewConstMetric(queueEnqueueBytesDesc, prometheus.CounterValue, item.Value, append(l, item.Threshold)...)
+		case item.Type == "drop":
+			ch <- prometheus.MustNewConstMetric(queueDropBytesDesc, prometheus.CounterValue, item.Value, append(l, item.Threshold)...)
+		default:
+			ch <- prometheus.MustNewConstMetric(queueOtherDropBytesDesc, prometheus.CounterValue, item.Value, append(l, item.Type)...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package qos
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/moeinshahcheraghi/cisco_exporter/util"
+)
+
+var (
+	physicalPortRegexp  = regexp.MustCompile(`^[A-Za-z-]+(\d+)/\d+/\d+$`)
+	thresholdColRegexp  = regexp.MustCompile(`^(Enqueue|Drop)-TH(\d+)$`)
+	mlsInterfaceRegexp  = regexp.MustCompile(`^([A-Za-z][A-Za-z-]*\d[\w/.:-]*)\s*(?:\(.*\))?\s*$`)
+	mlsQueueRowRegexp   = regexp.MustCompile(`^\s*queue (\d+):((?:\s+\d+)+)\s*$`)
+	mlsEnqueuedRegexp   = regexp.MustCompile(`^\s*output queues enqueued:`)
+	mlsDroppedRegexp    = regexp.MustCompile(`^\s*output queues dropped:`)
+	mlsOtherBlockRegexp = regexp.MustCompile(`^\s*(?:dscp|cos|Policer):`)
+)
+
+// ParseUpInterfaces parses the output of 'show ip interface brief' and returns the physical stack ports
+// which are up/up together with the stack member they belong to
+func ParseUpInterfaces(ostype string, output string) (map[string]string, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.IOS {
+		return nil, errors.New("'show ip interface brief' is not implemented for " + ostype)
+	}
+	items := make(map[string]string)
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		if fields[len(fields)-1] != "up" || fields[len(fields)-2] != "up" {
+			continue
+		}
+		matches := physicalPortRegexp.FindStringSubmatch(fields[0])
+		if matches == nil {
+			continue
+		}
+		items[fields[0]] = matches[1]
+	}
+	return items, nil
+}
+
+// ParseQueueStats parses the output of 'show platform hardware fed switch N qos queue stats interface X'
+// and returns the enqueue and drop counters per queue and threshold (all values in bytes)
+func ParseQueueStats(ostype string, iface string, output string) ([]QueueCounter, error) {
+	if ostype != rpc.IOSXE {
+		return nil, errors.New("'show platform hardware fed qos queue stats' is not implemented for " + ostype)
+	}
+	items := []QueueCounter{}
+	section := ""
+	var columns []string
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if strings.Contains(line, "Enqueue Counters") {
+			section, columns = "enqueue", nil
+			continue
+		}
+		if strings.Contains(line, "Drop Counters") {
+			section, columns = "drop", nil
+			continue
+		}
+		if section == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if fields[0] == "Q" || fields[0] == "Queue" {
+			columns = fields[1:]
+			continue
+		}
+		if columns == nil || !allNumeric(fields) {
+			continue
+		}
+		for i, col := range columns {
+			if i+1 >= len(fields) {
+				break
+			}
+			item := QueueCounter{
+				Interface: iface,
+				Queue:     fields[0],
+				Value:     util.Str2float64(fields[i+1]),
+			}
+			if matches := thresholdColRegexp.FindStringSubmatch(col); matches != nil {
+				item.Type = strings.ToLower(matches[1])
+				item.Threshold = matches[2]
+			} else if col == "Buffers" {
+				item.Type = "buffers"
+			} else if section == "drop" && strings.HasSuffix(col, "Drop") {
+				item.Type = strings.ToLower(strings.TrimSuffix(col, "Drop"))
+			} else {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// ParseMLSQueueStats parses the output of 'show mls qos interface statistics' and returns the
+// enqueued and dropped output queue counters per interface, queue and threshold (all values in packets)
+func ParseMLSQueueStats(ostype string, output string) ([]QueueCounter, error) {
+	if ostype != rpc.IOS {
+		return nil, errors.New("'show mls qos interface statistics' is not implemented for " + ostype)
+	}
+	items := []QueueCounter{}
+	iface := ""
+	section := ""
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if matches := mlsInterfaceRegexp.FindStringSubmatch(line); matches != nil {
+			iface = matches[1]
+			section = ""
+			continue
+		}
+		if mlsEnqueuedRegexp.MatchString(line) {
+			section = "enqueue"
+			continue
+		}
+		if mlsDroppedRegexp.MatchString(line) {
+			section = "drop"
+			continue
+		}
+		if mlsOtherBlockRegexp.MatchString(line) {
+			section = ""
+			continue
+		}
+		if iface == "" || section == "" {
+			continue
+		}
+		matches := mlsQueueRowRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		for i, value := range strings.Fields(matches[2]) {
+			items = append(items, QueueCounter{
+				Interface: iface,
+				Queue:     matches[1],
+				Threshold: strconv.Itoa(i + 1),
+				Type:      section,
+				Value:     util.Str2float64(value),
+			})
+		}
+	}
+	return items, nil
+}
+
+func allNumeric(fields []string) bool {
+	for _, f := range fields {
+		if _, err := strconv.ParseUint(f, 10, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
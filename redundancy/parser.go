@@ -0,0 +1,138 @@
+package redundancy
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/moeinshahcheraghi/cisco_exporter/util"
+)
+
+var (
+	stateRegexp     = regexp.MustCompile(`^(my|peer) state\s*=\s*\d+\s*-\s*(.+)$`)
+	keyValueRegexp  = regexp.MustCompile(`^(.+?)\s*[=:]\s*(.*)$`)
+	nxosMySupRegexp = regexp.MustCompile(`^This supervisor`)
+	nxosPeerRegexp  = regexp.MustCompile(`^Other supervisor`)
+)
+
+// Parse parses the output of 'show redundancy' and 'show redundancy states' (IOS, IOS XE)
+// or 'show system redundancy status' (NX-OS)
+func Parse(ostype string, output string) (Redundancy, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return Redundancy{}, errors.New("'show redundancy' is not implemented for " + ostype)
+	}
+	var r Redundancy
+	if ostype == rpc.NXOS {
+		r = parseNXOS(output)
+	} else {
+		r = parseIOS(output)
+	}
+	r.HasPeer = hasPeer(r)
+	return r, nil
+}
+
+// hasPeer reports whether there is a standby supervisor, standalone switches report their missing peer
+// as 'DISABLED' (IOS, IOS XE) or 'Not present' (NX-OS)
+func hasPeer(r Redundancy) bool {
+	switch strings.ToLower(r.PeerState) {
+	case "", "disabled", "non-redundant", "not present":
+		return false
+	}
+	switch strings.ToLower(r.OperatingMode) {
+	case "simplex", "none", "non-redundant":
+		return false
+	}
+	return true
+}
+
+func parseIOS(output string) Redundancy {
+	r := Redundancy{}
+	section := ""
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Current Processor Information") {
+			section = "my"
+			continue
+		}
+		if strings.HasPrefix(line, "Peer Processor Information") {
+			section = "peer"
+			continue
+		}
+		if matches := stateRegexp.FindStringSubmatch(line); matches != nil {
+			if matches[1] == "my" {
+				r.MyState = strings.TrimSpace(matches[2])
+			} else {
+				r.PeerState = strings.TrimSpace(matches[2])
+			}
+			continue
+		}
+		matches := keyValueRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		key, value := matches[1], strings.TrimSpace(matches[2])
+		switch key {
+		case "Switchovers system experienced":
+			r.Switchovers, r.HasSwitchovers = util.Str2float64(value), true
+		case "Operating Redundancy Mode", "Redundancy Mode (Operational)":
+			r.OperatingMode = value
+		case "Configured Redundancy Mode", "Redundancy Mode (Configured)":
+			r.ConfiguredMode = value
+		case "Current Software state":
+			// 'show redundancy states' is more precise, only use these when it was not available
+			if section == "my" && r.MyState == "" {
+				r.MyState = value
+			} else if section == "peer" && r.PeerState == "" {
+				r.PeerState = value
+			}
+		case "Uptime in current state":
+			if section == "my" {
+				r.StateUptime, r.HasStateUptime = util.Duration2Seconds(value), true
+			}
+		}
+	}
+	r.PeerStandbyHot = r.PeerState == "STANDBY HOT"
+	return r
+}
+
+func parseNXOS(output string) Redundancy {
+	r := Redundancy{}
+	section := ""
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case nxosMySupRegexp.MatchString(line):
+			section = "my"
+			continue
+		case nxosPeerRegexp.MatchString(line):
+			section = "peer"
+			continue
+		case strings.HasPrefix(line, "Redundancy mode"):
+			section = "mode"
+			continue
+		}
+		matches := keyValueRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		key, value := matches[1], strings.TrimSpace(matches[2])
+		switch {
+		case section == "mode" && key == "administrative":
+			r.ConfiguredMode = value
+		case section == "mode" && key == "operational":
+			r.OperatingMode = value
+		case section == "my" && key == "Redundancy state":
+			r.MyState = value
+		case section == "peer" && key == "Redundancy state":
+			r.PeerState = value
+		case section == "peer" && key == "Supervisor state":
+			r.PeerStandbyHot = value == "HA standby"
+		}
+	}
+	return r
+}
@@ -0,0 +1,100 @@
+package redundancy
+
+import (
+	"log"
+	"time"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prefix string = "cisco_redundancy_"
+
+var (
+	stateDesc          *prometheus.Desc
+	peerStandbyHotDesc *prometheus.Desc
+	modeDesc           *prometheus.Desc
+	switchoversDesc    *prometheus.Desc
+	lastSwitchoverDesc *prometheus.Desc
+)
+
+func init() {
+	l := []string{"target"}
+	stateDesc = prometheus.NewDesc(prefix+"state_info", "Redundancy state of this and the peer supervisor", append(l, "unit", "state"), nil)
+	peerStandbyHotDesc = prometheus.NewDesc(prefix+"peer_standby_hot", "Peer supervisor is ready to take over (1 STANDBY HOT / HA standby, 0 otherwise), only exported if there is a peer", l, nil)
+	modeDesc = prometheus.NewDesc(prefix+"mode_info", "Operating and configured redundancy mode (e.g. SSO, RPR, HA)", append(l, "operating", "configured"), nil)
+	switchoversDesc = prometheus.NewDesc(prefix+"switchovers_total", "Number of switchovers the system experienced", l, nil)
+	lastSwitchoverDesc = prometheus.NewDesc(prefix+"last_switchover_timestamp_seconds", "Time of the last switchover as unix timestamp", l, nil)
+}
+
+type redundancyCollector struct{}
+
+// NewCollector creates a new collector
+func NewCollector() collector.RPCCollector {
+	return &redundancyCollector{}
+}
+
+// Name returns the name of the collector
+func (*redundancyCollector) Name() string {
+	return "Redundancy"
+}
+
+// Describe describes the metrics
+func (*redundancyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stateDesc
+	ch <- peerStandbyHotDesc
+	ch <- modeDesc
+	ch <- switchoversDesc
+	ch <- lastSwitchoverDesc
+}
+
+// Collect collects metrics from Cisco
+func (c *redundancyCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	cmds := []string{"show redundancy", "show redundancy states"}
+	if client.OSType == rpc.NXOS {
+		cmds = []string{"show system redundancy status"}
+	}
+	out := ""
+	for _, cmd := range cmds {
+		o, err := client.RunCommand(cmd)
+		if err != nil {
+			return err
+		}
+		out += o + "\n"
+	}
+	r, err := Parse(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("Parse redundancy for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+
+	if r.MyState != "" {
+		ch <- prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 1, append(labelValues, "my", r.MyState)...)
+	}
+	if r.PeerState != "" {
+		ch <- prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, 1, append(labelValues, "peer", r.PeerState)...)
+	}
+	if r.HasPeer {
+		peerHot := 0.0
+		if r.PeerStandbyHot {
+			peerHot = 1
+		}
+		ch <- prometheus.MustNewConstMetric(peerStandbyHotDesc, prometheus.GaugeValue, peerHot, labelValues...)
+	}
+	if r.OperatingMode != "" || r.ConfiguredMode != "" {
+		ch <- prometheus.MustNewConstMetric(modeDesc, prometheus.GaugeValue, 1, append(labelValues, r.OperatingMode, r.ConfiguredMode)...)
+	}
+	if r.HasSwitchovers {
+		ch <- prometheus.MustNewConstMetric(switchoversDesc, prometheus.CounterValue, r.Switchovers, labelValues...)
+		// the active supervisor has been in its current state since the last switchover
+		if r.Switchovers > 0 && r.HasStateUptime {
+			last := float64(time.Now().Unix()) - r.StateUptime
+			ch <- prometheus.MustNewConstMetric(lastSwitchoverDesc, prometheus.GaugeValue, last, labelValues...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package redundancy
+
+// Redundancy holds the supervisor redundancy state of a device
+type Redundancy struct {
+	MyState        string
+	PeerState      string
+	OperatingMode  string
+	ConfiguredMode string
+	Switchovers    float64
+	HasSwitchovers bool
+	StateUptime    float64
+	HasStateUptime bool
+	PeerStandbyHot bool
+	HasPeer        bool
+}
@@ -0,0 +1,181 @@
+package rpc
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Driver describes how to detect and talk to one Cisco platform
+type Driver struct {
+	// Name identifies the driver, it equals the OS type unless several drivers share an OS type
+	Name   string
+	OSType string
+
+	// Detect reports whether the output of 'show version' belongs to this platform
+	Detect func(version string) bool
+
+	// Probe is run instead of 'show version' on platforms which do not support it, Detect is applied to its output
+	Probe string
+
+	// Wireless marks wireless LAN controllers
+	Wireless bool
+
+	// Platforms are model prefixes selecting this driver over the others of the OS type when configured as platform hint
+	Platforms []string
+
+	// Prompt matches the end of the CLI output, i.e. the prompt of the platform
+	Prompt *regexp.Regexp
+
+	// Pagination is the command disabling paging of the CLI output
+	Pagination string
+
+	// Commands replaces commands run by the collectors (keyed by the IOS command) with the platform syntax
+	Commands map[string]string
+}
+
+var drivers []*Driver
+
+func init() {
+	// order matters: IOS XE and IOS XR output also contains strings of the generic IOS check
+	// and the Catalyst 9800 is an IOS XE device
+	RegisterDriver(&Driver{
+		Name:   "WLC9800",
+		OSType: IOSXE,
+		Detect: func(version string) bool {
+			return (strings.Contains(version, "IOS XE") || strings.Contains(version, "IOS-XE")) && strings.Contains(version, "C9800")
+		},
+		Wireless:   true,
+		Platforms:  []string{"C9800"},
+		Prompt:     regexp.MustCompile(`.+#\s?$`),
+		Pagination: "terminal length 0",
+	})
+	RegisterDriver(&Driver{
+		Name:   IOSXE,
+		OSType: IOSXE,
+		Detect: func(version string) bool {
+			return strings.Contains(version, "IOS XE") || strings.Contains(version, "IOS-XE")
+		},
+		Prompt:     regexp.MustCompile(`.+#\s?$`),
+		Pagination: "terminal length 0",
+	})
+	RegisterDriver(&Driver{
+		Name:       IOSXR,
+		OSType:     IOSXR,
+		Detect:     func(version string) bool { return strings.Contains(version, "IOS XR") },
+		Prompt:     regexp.MustCompile(`(?:RP|LC)/\d+/[^/\s]+/CPU\d+:[^#\s]+#\s?$`),
+		Pagination: "terminal length 0",
+		Commands: map[string]string{
+			"show process cpu":    "show processes cpu | include utilization",
+			"show process memory": "show memory summary",
+		},
+	})
+	RegisterDriver(&Driver{
+		Name:       NXOS,
+		OSType:     NXOS,
+		Detect:     func(version string) bool { return strings.Contains(version, "NX-OS") },
+		Prompt:     regexp.MustCompile(`.+#\s?$`),
+		Pagination: "terminal length 0",
+		Commands: map[string]string{
+			"show ipv6 neighbors": "show ipv6 neighbor",
+			"show process cpu":    "show processes cpu",
+			"show process memory": "show system resources",
+		},
+	})
+	RegisterDriver(&Driver{
+		Name:   ASA,
+		OSType: ASA,
+		Detect: func(version string) bool {
+			return strings.Contains(version, "Adaptive Security Appliance") || strings.Contains(version, "Threat Defense")
+		},
+		// FTD drops into its own CLI with a bare '>' prompt
+		Prompt:     regexp.MustCompile(`.*[#>]\s?$`),
+		Pagination: "terminal pager 0",
+		Commands: map[string]string{
+			"show process cpu":    "show cpu usage",
+			"show process memory": "show memory",
+		},
+	})
+	RegisterDriver(&Driver{
+		Name:       IOS,
+		OSType:     IOS,
+		Detect:     func(version string) bool { return strings.Contains(version, "IOS Software") },
+		Prompt:     regexp.MustCompile(`.+#\s?$`),
+		Pagination: "terminal length 0",
+	})
+	RegisterDriver(&Driver{
+		Name:       AIREOS,
+		OSType:     AIREOS,
+		Detect:     func(sysinfo string) bool { return strings.Contains(sysinfo, "Cisco Controller") },
+		Probe:      "show sysinfo",
+		Prompt:     regexp.MustCompile(`\(.+\)\s?>\s?$`),
+		Pagination: "config paging disable",
+	})
+}
+
+// RegisterDriver adds a platform driver. Drivers are tried in the order they were registered.
+func RegisterDriver(d *Driver) {
+	drivers = append(drivers, d)
+}
+
+// DriverByName returns the driver with the given name or nil if none is registered
+func DriverByName(name string) *Driver {
+	for _, d := range drivers {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// DriverForHint returns the driver of a configured OS type (or driver name) and platform hint, nil if there is none.
+// The OS type is matched ignoring case, blanks and dashes, so "IOS-XE" and "iosxe" are the same.
+func DriverForHint(ostype, platform string) *Driver {
+	ostype = NormalizeOSType(ostype)
+	platform = strings.ToUpper(platform)
+	if platform != "" {
+		for _, d := range drivers {
+			if d.OSType != ostype {
+				continue
+			}
+			for _, p := range d.Platforms {
+				if strings.HasPrefix(platform, p) {
+					return d
+				}
+			}
+		}
+	}
+	return DriverByName(ostype)
+}
+
+// NormalizeOSType returns the OS type constant for an OS type as written by users (e.g. "NX-OS", "ios xe")
+func NormalizeOSType(ostype string) string {
+	return strings.NewReplacer("-", "", " ", "", "_", "").Replace(strings.ToUpper(ostype))
+}
+
+// detectDriver returns the first driver matching the output of 'show version'
+func detectDriver(version string) *Driver {
+	for _, d := range drivers {
+		if d.Detect(version) {
+			return d
+		}
+	}
+	return nil
+}
+
+// command returns the command to run on the platform for a command of a collector.
+// Arguments following a replaced command (e.g. 'vrf X') are kept.
+func (d *Driver) command(cmd string) string {
+	if c, ok := d.Commands[cmd]; ok {
+		return c
+	}
+	match := ""
+	for k := range d.Commands {
+		if strings.HasPrefix(cmd, k+" ") && len(k) > len(match) {
+			match = k
+		}
+	}
+	if match != "" {
+		return d.Commands[match] + cmd[len(match):]
+	}
+	return cmd
+}
@@ -3,7 +3,6 @@ package rpc
 import (
 	"errors"
 	"fmt"
-	"strings"
 	"log"
 	"github.com/moeinshahcheraghi/cisco_exporter/connector"
 )
@@ -12,7 +11,9 @@ const (
 	IOSXE string = "IOSXE"
 	NXOS  string = "NXOS"
 	IOS   string = "IOS"
-	
+	IOSXR string = "IOSXR"
+	ASA   string = "ASA"
+	AIREOS string = "AIREOS"
 )
 
 // Client sends commands to a Cisco device
@@ -20,7 +21,12 @@ type Client struct {
     conn   *connector.SSHConnection
     Debug  bool
     OSType string
+    // Wireless is set for wireless LAN controllers sharing the OS type of a switch (Catalyst 9800)
+    Wireless bool
     cache  map[string]string
+    driver *Driver
+    // overrides replaces commands per driver name or OS type, keyed by the command of the collector
+    overrides map[string]map[string]string
 }
 
 // NewClient creates a new client connection
@@ -38,18 +44,79 @@ func (c *Client) Identify() error {
 	if err != nil {
 		return err
 	}
-	switch {
-	case strings.Contains(output, "IOS XE"):
-		c.OSType = IOSXE
-	case strings.Contains(output, "NX-OS"):
-		c.OSType = NXOS
-	case strings.Contains(output, "IOS Software"):
-		c.OSType = IOS
-	default:
+	d := detectDriver(output)
+	if d == nil {
+		d = c.probeDriver()
+	}
+	if d == nil {
 		return errors.New("Unknown OS")
 	}
 	if c.Debug {
-		log.Printf("Host %s identified as: %s\n", c.conn.Host, c.OSType)
+		log.Printf("Host %s identified as: %s\n", c.conn.Host, d.Name)
+	}
+	return c.useDriver(d)
+}
+
+// IdentifyAs skips the detection and uses the driver of a configured OS type and optional platform (model)
+func (c *Client) IdentifyAs(ostype, platform string) error {
+	d := DriverForHint(ostype, platform)
+	if d == nil {
+		return errors.New("Unknown OS type " + ostype)
+	}
+	if c.Debug {
+		log.Printf("Host %s configured as: %s\n", c.conn.Host, d.Name)
+	}
+	return c.useDriver(d)
+}
+
+// SetCommandOverrides sets commands replacing the ones of the collectors, keyed by driver name or OS type
+func (c *Client) SetCommandOverrides(overrides map[string]map[string]string) {
+	c.overrides = make(map[string]map[string]string)
+	for ostype, commands := range overrides {
+		c.overrides[NormalizeOSType(ostype)] = commands
+	}
+}
+
+func (c *Client) useDriver(d *Driver) error {
+	c.OSType = d.OSType
+	c.Wireless = d.Wireless
+	c.driver = d
+	c.conn.SetPrompt(d.Prompt)
+	if d.Pagination != "" && d.Pagination != connector.DefaultPagination {
+		if _, err := c.conn.RunCommand(d.Pagination); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// command returns the command to run on the device for a command of a collector
+func (c *Client) command(cmd string) string {
+	if c.driver == nil {
+		return cmd
+	}
+	if o, ok := c.overrides[c.driver.Name][cmd]; ok {
+		return o
+	}
+	if o, ok := c.overrides[c.OSType][cmd]; ok {
+		return o
+	}
+	return c.driver.command(cmd)
+}
+
+// probeDriver runs the probe commands of platforms not supporting 'show version'
+func (c *Client) probeDriver() *Driver {
+	for _, d := range drivers {
+		if d.Probe == "" {
+			continue
+		}
+		output, err := c.RunCommand(d.Probe)
+		if err != nil {
+			continue
+		}
+		if d.Detect(output) {
+			return d
+		}
 	}
 	return nil
 }
@@ -65,7 +132,7 @@ func (c *Client) RunCommand(cmd string) (string, error) {
     if c.Debug {
         log.Printf("Running command on %s: %s\n", c.conn.Host, cmd)
     }
-    output, err := c.conn.RunCommand(fmt.Sprintf("%s", cmd))
+    output, err := c.conn.RunCommand(fmt.Sprintf("%s", c.command(cmd)))
     if err == nil {
         c.cache[cmd] = output
     }
@@ -0,0 +1,131 @@
+package secret
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultRefreshInterval is the time after which a resolved secret is fetched again
+const DefaultRefreshInterval = 5 * time.Minute
+
+const (
+	envPrefix   = "env:"
+	filePrefix  = "file:"
+	vaultPrefix = "vault:"
+)
+
+var defaultResolver = NewResolver(DefaultRefreshInterval)
+
+// Resolver resolves references to secrets and caches the values until they are due for a refresh
+type Resolver struct {
+	mu      sync.Mutex
+	refresh time.Duration
+	cache   map[string]*entry
+	vault   *vaultClient
+}
+
+type entry struct {
+	mu        sync.Mutex
+	value     string
+	fetched   bool
+	fetchedAt time.Time
+}
+
+// NewResolver creates a new resolver fetching the secrets again after the refresh interval
+func NewResolver(refresh time.Duration) *Resolver {
+	return &Resolver{
+		refresh: refresh,
+		cache:   make(map[string]*entry),
+		vault:   newVaultClientFromEnv(),
+	}
+}
+
+// SetRefreshInterval sets the refresh interval of the resolver used by Resolve
+func SetRefreshInterval(refresh time.Duration) {
+	defaultResolver.mu.Lock()
+	defer defaultResolver.mu.Unlock()
+	defaultResolver.refresh = refresh
+}
+
+// Resolve resolves a reference with the default resolver
+func Resolve(s string) (string, error) {
+	return defaultResolver.Resolve(s)
+}
+
+// IsRef reports whether s is a reference to a secret rather than the value itself
+func IsRef(s string) bool {
+	return strings.HasPrefix(s, envPrefix) || strings.HasPrefix(s, filePrefix) || strings.HasPrefix(s, vaultPrefix)
+}
+
+// Resolve returns the value of a reference:
+//
+//	env:NAME                   environment variable NAME
+//	file:/path                 content of the file without the trailing line break
+//	vault:secret/data/x#field  field of a secret in HashiCorp Vault (KV version 1 or 2)
+//
+// Any other string is returned as is. If a secret can not be fetched again, its last value is used.
+func (r *Resolver) Resolve(s string) (string, error) {
+	if !IsRef(s) {
+		return s, nil
+	}
+
+	// only the resolutions of the same reference wait for each other
+	e, refresh := r.entry(s)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.fetched && time.Since(e.fetchedAt) < refresh {
+		return e.value, nil
+	}
+
+	value, err := r.fetch(s)
+	if err != nil {
+		if e.fetched {
+			log.Printf("Could not refresh secret %s, using the last value: %s\n", s, err.Error())
+			return e.value, nil
+		}
+		return "", errors.Wrapf(err, "could not resolve secret %s", s)
+	}
+
+	e.value = value
+	e.fetched = true
+	e.fetchedAt = time.Now()
+	return value, nil
+}
+
+func (r *Resolver) entry(ref string) (*entry, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, found := r.cache[ref]
+	if !found {
+		e = &entry{}
+		r.cache[ref] = e
+	}
+	return e, r.refresh
+}
+
+func (r *Resolver) fetch(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, envPrefix):
+		value, found := os.LookupEnv(strings.TrimPrefix(ref, envPrefix))
+		if !found {
+			return "", errors.New("environment variable is not set")
+		}
+		return value, nil
+	case strings.HasPrefix(ref, filePrefix):
+		b, err := ioutil.ReadFile(strings.TrimPrefix(ref, filePrefix))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	default:
+		return r.vault.read(strings.TrimPrefix(ref, vaultPrefix))
+	}
+}
@@ -0,0 +1,213 @@
+package secret
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// vaultServer is a stub of the Vault API serving a KV version 2 secret at secret/data/network
+// and a KV version 1 secret at kv/network
+type vaultServer struct {
+	*httptest.Server
+	password atomic.Value
+	status   int32
+	requests int32
+}
+
+func newVaultServer(t *testing.T) *vaultServer {
+	v := &vaultServer{status: http.StatusOK}
+	v.password.Store("secret1")
+	v.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&v.requests, 1)
+		if status := int(atomic.LoadInt32(&v.status)); status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/network":
+			fmt.Fprintf(w, `{"data":{"data":{"password":%q,"port":22},"metadata":{"version":3}}}`, v.password.Load())
+		case "/v1/kv/network":
+			fmt.Fprint(w, `{"data":{"password":"kv1-secret"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(v.Close)
+	return v
+}
+
+func (v *vaultServer) resolver(refresh time.Duration) *Resolver {
+	r := NewResolver(refresh)
+	r.vault = &vaultClient{addr: v.URL, token: "token", client: v.Client()}
+	return r
+}
+
+func TestResolveVault(t *testing.T) {
+	v := newVaultServer(t)
+	r := v.resolver(time.Minute)
+
+	tests := []struct {
+		ref      string
+		expected string
+		err      string
+	}{
+		{ref: "vault:secret/data/network#password", expected: "secret1"},
+		{ref: "vault:secret/data/network#port", expected: "22"},
+		{ref: "vault:/kv/network#password", expected: "kv1-secret"},
+		{ref: "vault:secret/data/network#username", err: "field username not found in secret/data/network"},
+		{ref: "vault:kv/other#password", err: "vault returned 404 Not Found for kv/other"},
+		{ref: "vault:kv/network", err: "vault reference must be <path>#<field>"},
+	}
+	for _, test := range tests {
+		t.Run(test.ref, func(t *testing.T) {
+			value, err := r.Resolve(test.ref)
+			if test.err != "" {
+				if err == nil || !strings.HasSuffix(err.Error(), test.err) {
+					t.Fatalf("expected error %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, value)
+			}
+		})
+	}
+}
+
+func TestResolveVaultForbidden(t *testing.T) {
+	v := newVaultServer(t)
+	r := v.resolver(time.Minute)
+	r.vault.token = "wrong"
+
+	_, err := r.Resolve("vault:secret/data/network#password")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("expected 403 error, got %v", err)
+	}
+}
+
+func TestResolveRefresh(t *testing.T) {
+	v := newVaultServer(t)
+	r := v.resolver(50 * time.Millisecond)
+	ref := "vault:secret/data/network#password"
+
+	resolve := func(expected string) {
+		t.Helper()
+		value, err := r.Resolve(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != expected {
+			t.Fatalf("expected %q, got %q", expected, value)
+		}
+	}
+
+	resolve("secret1")
+	v.password.Store("secret2")
+	resolve("secret1")
+	if n := atomic.LoadInt32(&v.requests); n != 1 {
+		t.Fatalf("expected the cached value to be used, vault got %d requests", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	resolve("secret2")
+
+	// a failing refresh keeps the last value
+	atomic.StoreInt32(&v.status, http.StatusServiceUnavailable)
+	time.Sleep(60 * time.Millisecond)
+	resolve("secret2")
+	if n := atomic.LoadInt32(&v.requests); n != 3 {
+		t.Fatalf("expected a refresh attempt, vault got %d requests", n)
+	}
+
+	// without a last value the error is returned
+	if _, err := r.Resolve("vault:kv/network#password"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestResolveDoesNotWaitForOtherReferences(t *testing.T) {
+	release := make(chan struct{})
+	v := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprint(w, `{"data":{"password":"slow"}}`)
+	}))
+	defer v.Close()
+	defer close(release)
+
+	r := NewResolver(time.Minute)
+	r.vault = &vaultClient{addr: v.URL, client: v.Client()}
+	go r.Resolve("vault:kv/slow#password")
+	time.Sleep(20 * time.Millisecond)
+
+	os.Setenv("SECRET_TEST_FAST", "fast")
+	defer os.Unsetenv("SECRET_TEST_FAST")
+	done := make(chan string)
+	go func() {
+		value, _ := r.Resolve("env:SECRET_TEST_FAST")
+		done <- value
+	}()
+
+	select {
+	case value := <-done:
+		if value != "fast" {
+			t.Fatalf("expected %q, got %q", "fast", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("resolution waited for the fetch of another reference")
+	}
+}
+
+func TestResolveEnvAndFile(t *testing.T) {
+	os.Setenv("SECRET_TEST_PASSWORD", "from-env")
+	defer os.Unsetenv("SECRET_TEST_PASSWORD")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "password")
+	if err := ioutil.WriteFile(file, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewResolver(time.Minute)
+	tests := []struct {
+		ref      string
+		expected string
+		err      bool
+	}{
+		{ref: "plain", expected: "plain"},
+		{ref: "env:SECRET_TEST_PASSWORD", expected: "from-env"},
+		{ref: "env:SECRET_TEST_UNSET", err: true},
+		{ref: "file:" + file, expected: "from-file"},
+		{ref: "file:" + filepath.Join(dir, "missing"), err: true},
+	}
+	for _, test := range tests {
+		value, err := r.Resolve(test.ref)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: expected error, got %q", test.ref, value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.ref, err)
+			continue
+		}
+		if value != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.ref, test.expected, value)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package stackport
+
+// StackMember is a switch of a StackWise stack as listed by 'show switch'
+type StackMember struct {
+	Switch    string
+	Role      string
+	MAC       string
+	Priority  float64
+	HWVersion string
+	State     string
+	Ports     []string
+}
+
+// PowerStack is a StackPower stack as listed by 'show stack-power'
+type PowerStack struct {
+	Name      string
+	Mode      string
+	Topology  string
+	Total     float64
+	Reserved  float64
+	Allocated float64
+	Available float64
+}
+
+// Stack holds the members, ring and power stacks of a switch stack
+type Stack struct {
+	Members     []StackMember
+	PowerStacks []PowerStack
+}
+
+// FullRing reports whether all stack ports of all members are up, i.e. the stack ring is closed
+func (s Stack) FullRing() bool {
+	for _, m := range s.Members {
+		for _, p := range m.Ports {
+			if p != "OK" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RoleValue maps the role of a member to 1 (active/master), 2 (standby) or 3 (member)
+func (m StackMember) RoleValue() float64 {
+	switch m.Role {
+	case "Active", "Master":
+		return 1
+	case "Standby":
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,109 @@
+package stackport
+
+import (
+	"log"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const memberPrefix string = "cisco_stack_"
+
+var (
+	memberInfoDesc     *prometheus.Desc
+	memberRoleDesc     *prometheus.Desc
+	memberStateDesc    *prometheus.Desc
+	memberPriorityDesc *prometheus.Desc
+	ringFullDesc       *prometheus.Desc
+	powerInfoDesc      *prometheus.Desc
+	powerWattsDesc     *prometheus.Desc
+)
+
+func init() {
+	l := []string{"target", "switch"}
+	memberInfoDesc = prometheus.NewDesc(memberPrefix+"member_info", "MAC address and hardware version of a stack member", append(l, "mac", "hw_version"), nil)
+	memberRoleDesc = prometheus.NewDesc(memberPrefix+"member_role", "Role of a stack member (1 active/master, 2 standby, 3 member)", append(l, "role"), nil)
+	memberStateDesc = prometheus.NewDesc(memberPrefix+"member_state", "State of a stack member (1 Ready, 0 otherwise e.g. Provisioned, Removed, Version-Mismatch)", append(l, "state"), nil)
+	memberPriorityDesc = prometheus.NewDesc(memberPrefix+"member_priority", "Stack priority of a member", l, nil)
+	ringFullDesc = prometheus.NewDesc(memberPrefix+"ring_full", "Stack ring topology (1 full ring, 0 half ring)", []string{"target"}, nil)
+	powerInfoDesc = prometheus.NewDesc(memberPrefix+"power_info", "Mode and topology of a power stack", []string{"target", "power_stack", "mode", "topology"}, nil)
+	powerWattsDesc = prometheus.NewDesc(memberPrefix+"power_watts", "Total, reserved, allocated and available power of a power stack in watts", []string{"target", "power_stack", "type"}, nil)
+}
+
+type stackMemberCollector struct{}
+
+// NewMemberCollector creates a new collector for stack member health
+func NewMemberCollector() collector.RPCCollector {
+	return &stackMemberCollector{}
+}
+
+// Name returns the name of the collector
+func (*stackMemberCollector) Name() string {
+	return "StackMembers"
+}
+
+// Describe describes the metrics
+func (*stackMemberCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- memberInfoDesc
+	ch <- memberRoleDesc
+	ch <- memberStateDesc
+	ch <- memberPriorityDesc
+	ch <- ringFullDesc
+	ch <- powerInfoDesc
+	ch <- powerWattsDesc
+}
+
+// Collect collects metrics from Cisco
+func (c *stackMemberCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show switch detail")
+	if err != nil {
+		return err
+	}
+	power, err := client.RunCommand("show stack-power")
+	if err != nil {
+		if client.Debug {
+			log.Printf("Stack power command on %s: %s\n", labelValues[0], err.Error())
+		}
+	} else {
+		out += "\n" + power
+	}
+	stack, err := ParseMembers(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("ParseMembers for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+
+	hasPorts := false
+	for _, m := range stack.Members {
+		l := append(labelValues, m.Switch)
+		ch <- prometheus.MustNewConstMetric(memberInfoDesc, prometheus.GaugeValue, 1, append(l, m.MAC, m.HWVersion)...)
+		ch <- prometheus.MustNewConstMetric(memberRoleDesc, prometheus.GaugeValue, m.RoleValue(), append(l, m.Role)...)
+		ready := 0.0
+		if m.State == "Ready" {
+			ready = 1
+		}
+		ch <- prometheus.MustNewConstMetric(memberStateDesc, prometheus.GaugeValue, ready, append(l, m.State)...)
+		ch <- prometheus.MustNewConstMetric(memberPriorityDesc, prometheus.GaugeValue, m.Priority, l...)
+		hasPorts = hasPorts || len(m.Ports) > 0
+	}
+	if len(stack.Members) > 1 && hasPorts {
+		full := 0.0
+		if stack.FullRing() {
+			full = 1
+		}
+		ch <- prometheus.MustNewConstMetric(ringFullDesc, prometheus.GaugeValue, full, labelValues...)
+	}
+	for _, p := range stack.PowerStacks {
+		ch <- prometheus.MustNewConstMetric(powerInfoDesc, prometheus.GaugeValue, 1, append(labelValues, p.Name, p.Mode, p.Topology)...)
+		l := append(labelValues, p.Name)
+		ch <- prometheus.MustNewConstMetric(powerWattsDesc, prometheus.GaugeValue, p.Total, append(l, "total")...)
+		ch <- prometheus.MustNewConstMetric(powerWattsDesc, prometheus.GaugeValue, p.Reserved, append(l, "reserved")...)
+		ch <- prometheus.MustNewConstMetric(powerWattsDesc, prometheus.GaugeValue, p.Allocated, append(l, "allocated")...)
+		ch <- prometheus.MustNewConstMetric(powerWattsDesc, prometheus.GaugeValue, p.Available, append(l, "available")...)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package stackport
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/moeinshahcheraghi/cisco_exporter/util"
+)
+
+var (
+	memberRegexp     = regexp.MustCompile(`^\*?\s*(\d+)\s+(\S+)\s+([0-9a-fA-F]{4}\.[0-9a-fA-F]{4}\.[0-9a-fA-F]{4})\s+(\d+)\s+(\S+)\s+(.+?)\s*$`)
+	memberPortRegexp = regexp.MustCompile(`^\s*(\d+)\s+(\S+)\s+(\S+)\s+\S+\s+\S+\s*$`)
+	powerStackRegexp = regexp.MustCompile(`^(\S+)\s+(\S+)\s+(\S+)\s+(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\s+\d+\s+\d+`)
+)
+
+// ParseMembers parses the output of 'show switch', 'show switch detail' and 'show stack-power'
+func ParseMembers(ostype string, output string) (Stack, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.IOS {
+		return Stack{}, errors.New("'show switch' is not implemented for " + ostype)
+	}
+
+	stack := Stack{}
+	members := make(map[string]int)
+	inPorts := false
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			inPorts = false
+			continue
+		}
+		if strings.Contains(line, "Port 1") && strings.Contains(line, "Port 2") && strings.HasPrefix(strings.TrimSpace(line), "Switch#") {
+			inPorts = true
+			continue
+		}
+
+		if matches := memberRegexp.FindStringSubmatch(line); matches != nil {
+			if _, found := members[matches[1]]; found {
+				continue
+			}
+			members[matches[1]] = len(stack.Members)
+			stack.Members = append(stack.Members, StackMember{
+				Switch:    matches[1],
+				Role:      matches[2],
+				MAC:       matches[3],
+				Priority:  util.Str2float64(matches[4]),
+				HWVersion: matches[5],
+				State:     matches[6],
+			})
+			continue
+		}
+		if matches := memberPortRegexp.FindStringSubmatch(line); matches != nil && inPorts {
+			if i, found := members[matches[1]]; found {
+				stack.Members[i].Ports = []string{matches[2], matches[3]}
+			}
+			continue
+		}
+		if matches := powerStackRegexp.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			stack.PowerStacks = append(stack.PowerStacks, PowerStack{
+				Name:      matches[1],
+				Mode:      matches[2],
+				Topology:  matches[3],
+				Total:     util.Str2float64(matches[4]),
+				Reserved:  util.Str2float64(matches[5]),
+				Allocated: util.Str2float64(matches[6]),
+				Available: util.Str2float64(matches[7]),
+			})
+		}
+	}
+
+	return stack, nil
+}
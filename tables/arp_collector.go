@@ -1,19 +1,37 @@
 package tables
 
 import (
+	"errors"
+	"log"
 	"regexp"
+	"strings"
+
 	"github.com/moeinshahcheraghi/cisco_exporter/collector"
 	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
-	"github.com/moeinshahcheraghi/cisco_exporter/util"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const prefix = "cisco_tables_"
 
 var (
-	arpEntriesDesc = prometheus.NewDesc(prefix+"arp_entries", "Number of ARP entries", []string{"target"}, nil)
+	arpEntriesDesc          = prometheus.NewDesc(prefix+"arp_entries", "Number of ARP entries", []string{"target"}, nil)
+	arpEntriesVRFDesc       = prometheus.NewDesc(prefix+"arp_entries_vrf", "Number of ARP entries per VRF", []string{"target", "vrf"}, nil)
+	arpEntriesInterfaceDesc = prometheus.NewDesc(prefix+"arp_entries_interface", "Number of ARP entries per VRF and interface", []string{"target", "vrf", "interface"}, nil)
+	arpIncompleteDesc       = prometheus.NewDesc(prefix+"arp_incomplete_entries", "Number of incomplete ARP entries per VRF", []string{"target", "vrf"}, nil)
+
+	arpIOSRegexp  = regexp.MustCompile(`^Internet\s+(\d+\.\d+\.\d+\.\d+)\s+\S+\s+(\S+)\s+\S+\s*(\S*)`)
+	arpNXOSRegexp = regexp.MustCompile(`^(\d+\.\d+\.\d+\.\d+)\s+\S+\s+(\S+)\s*(\S*)`)
 )
 
+// ARPEntry is a single entry of the ARP table
+type ARPEntry struct {
+	VRF        string
+	Address    string
+	MAC        string
+	Interface  string
+	Incomplete bool
+}
+
 type arpCollector struct{}
 
 func NewARPCollector() collector.RPCCollector {
@@ -26,23 +44,93 @@ func (*arpCollector) Name() string {
 
 func (*arpCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- arpEntriesDesc
+	ch <- arpEntriesVRFDesc
+	ch <- arpEntriesInterfaceDesc
+	ch <- arpIncompleteDesc
 }
 
 func (c *arpCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
-	out, err := client.RunCommand("show ip arp summary")
-	if err != nil {
-		return err
+	entries := []ARPEntry{}
+	vrfs := vrfsForClient(client)
+	for _, vrf := range vrfs {
+		cmd := "show ip arp"
+		if vrf != defaultVRF {
+			cmd += " vrf " + vrf
+		}
+		out, err := client.RunCommand(cmd)
+		if err != nil {
+			if vrf == defaultVRF {
+				return err
+			}
+			if client.Debug {
+				log.Printf("ARP command for vrf %s on %s: %s\n", vrf, labelValues[0], err.Error())
+			}
+			continue
+		}
+		items, err := ParseARP(client.OSType, vrf, out)
+		if err != nil {
+			if client.Debug {
+				log.Printf("ParseARP for %s: %s\n", labelValues[0], err.Error())
+			}
+			return nil
+		}
+		entries = append(entries, items...)
+	}
+
+	perVRF := make(map[string]float64)
+	incomplete := make(map[string]float64)
+	perInterface := make(map[[2]string]float64)
+	for _, vrf := range vrfs {
+		perVRF[vrf] = 0
+		incomplete[vrf] = 0
+	}
+	for _, e := range entries {
+		perVRF[e.VRF]++
+		if e.Incomplete {
+			incomplete[e.VRF]++
+			continue
+		}
+		perInterface[[2]string{e.VRF, e.Interface}]++
+	}
+
+	ch <- prometheus.MustNewConstMetric(arpEntriesDesc, prometheus.GaugeValue, float64(len(entries)), labelValues...)
+	for vrf, count := range perVRF {
+		ch <- prometheus.MustNewConstMetric(arpEntriesVRFDesc, prometheus.GaugeValue, count, append(labelValues, vrf)...)
+		ch <- prometheus.MustNewConstMetric(arpIncompleteDesc, prometheus.GaugeValue, incomplete[vrf], append(labelValues, vrf)...)
+	}
+	for k, count := range perInterface {
+		ch <- prometheus.MustNewConstMetric(arpEntriesInterfaceDesc, prometheus.GaugeValue, count, append(labelValues, k[0], k[1])...)
 	}
-	arpCount := parseARP(out)
-	ch <- prometheus.MustNewConstMetric(arpEntriesDesc, prometheus.GaugeValue, arpCount, labelValues...)
 	return nil
 }
 
-func parseARP(output string) float64 {
-	re := regexp.MustCompile(`(\d+) IP ARP entries`)
-	matches := re.FindStringSubmatch(output)
-	if matches != nil {
-		return util.Str2float64(matches[1])
+// ParseARP parses the output of 'show ip arp [vrf X]' and returns all entries of the table
+func ParseARP(ostype string, vrf string, output string) ([]ARPEntry, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return nil, errors.New("'show ip arp' is not implemented for " + ostype)
 	}
-	return 0
-}
\ No newline at end of file
+	re := arpIOSRegexp
+	if ostype == rpc.NXOS {
+		re = arpNXOSRegexp
+	}
+	entries := []ARPEntry{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := re.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		entry := ARPEntry{
+			VRF:       vrf,
+			Address:   matches[1],
+			MAC:       matches[2],
+			Interface: matches[3],
+		}
+		if strings.EqualFold(entry.MAC, "incomplete") {
+			entry.Incomplete = true
+			entry.MAC = ""
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
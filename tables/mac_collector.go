@@ -1,17 +1,33 @@
 package tables
 
 import (
+	"errors"
+	"log"
 	"regexp"
+	"strings"
+
 	"github.com/moeinshahcheraghi/cisco_exporter/collector"
 	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
-	"github.com/moeinshahcheraghi/cisco_exporter/util"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
-	macAddressesDesc = prometheus.NewDesc(prefix+"mac_addresses", "Number of MAC addresses", []string{"target"}, nil)
+	macAddressesDesc          = prometheus.NewDesc(prefix+"mac_addresses", "Number of MAC addresses", []string{"target"}, nil)
+	macAddressesVLANDesc      = prometheus.NewDesc(prefix+"mac_addresses_vlan", "Number of MAC addresses per VLAN and type", []string{"target", "vlan", "type"}, nil)
+	macAddressesInterfaceDesc = prometheus.NewDesc(prefix+"mac_addresses_interface", "Number of MAC addresses per interface and type", []string{"target", "interface", "type"}, nil)
+	macEntryDesc              = prometheus.NewDesc(prefix+"mac_entry_info", "MAC address table entry", []string{"target", "vlan", "mac", "interface", "type"}, nil)
+
+	macEntryRegexp = regexp.MustCompile(`^\s*(?:[*+GCO~]\s+)?(\S+)\s+([0-9a-fA-F]{4}\.[0-9a-fA-F]{4}\.[0-9a-fA-F]{4})\s+(\S+)\s+(.*\S)\s*$`)
 )
 
+// MACEntry is a single entry of the MAC address table
+type MACEntry struct {
+	VLAN      string
+	MAC       string
+	Type      string
+	Interface string
+}
+
 type macCollector struct{}
 
 func NewMACCollector() collector.RPCCollector {
@@ -24,24 +40,109 @@ func (*macCollector) Name() string {
 
 func (*macCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- macAddressesDesc
+	ch <- macAddressesVLANDesc
+	ch <- macAddressesInterfaceDesc
 }
 
 func (c *macCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
-	out, err := client.RunCommand("show mac address-table count")
+	out, err := client.RunCommand("show mac address-table")
+	if err != nil {
+		return err
+	}
+	entries, err := ParseMACTable(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("ParseMACTable for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+	ch <- prometheus.MustNewConstMetric(macAddressesDesc, prometheus.GaugeValue, float64(len(entries)), labelValues...)
+
+	perVLAN := make(map[[2]string]float64)
+	perInterface := make(map[[2]string]float64)
+	for _, e := range entries {
+		perVLAN[[2]string{e.VLAN, e.Type}]++
+		perInterface[[2]string{e.Interface, e.Type}]++
+	}
+	for k, count := range perVLAN {
+		ch <- prometheus.MustNewConstMetric(macAddressesVLANDesc, prometheus.GaugeValue, count, append(labelValues, k[0], k[1])...)
+	}
+	for k, count := range perInterface {
+		ch <- prometheus.MustNewConstMetric(macAddressesInterfaceDesc, prometheus.GaugeValue, count, append(labelValues, k[0], k[1])...)
+	}
+	return nil
+}
+
+type macEntriesCollector struct{}
+
+// NewMACEntriesCollector creates a collector exporting every MAC/VLAN/port tuple as an info metric
+func NewMACEntriesCollector() collector.RPCCollector {
+	return &macEntriesCollector{}
+}
+
+func (*macEntriesCollector) Name() string {
+	return "TablesMACEntries"
+}
+
+func (*macEntriesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- macEntryDesc
+}
+
+func (c *macEntriesCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show mac address-table")
 	if err != nil {
 		return err
 	}
-	macCount := parseMAC(out)
-	ch <- prometheus.MustNewConstMetric(macAddressesDesc, prometheus.GaugeValue, macCount, labelValues...)
+	entries, err := ParseMACTable(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("ParseMACTable for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+
+	seen := make(map[MACEntry]bool)
+	for _, e := range entries {
+		if seen[e] {
+			continue
+		}
+		seen[e] = true
+		ch <- prometheus.MustNewConstMetric(macEntryDesc, prometheus.GaugeValue, 1, append(labelValues, e.VLAN, e.MAC, e.Interface, e.Type)...)
+	}
 	return nil
 }
 
-func parseMAC(output string) float64 {
-	re := regexp.MustCompile(`Total Mac Addresses\s+:\s*(\d+)`)
-	matches := re.FindAllStringSubmatch(output, -1)
-	total := 0.0
-	for _, match := range matches {
-		total += util.Str2float64(match[1])
+// ParseMACTable parses the output of 'show mac address-table' (IOS, IOS XE and NX-OS) and returns all entries
+func ParseMACTable(ostype string, output string) ([]MACEntry, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return nil, errors.New("'show mac address-table' is not implemented for " + ostype)
+	}
+	entries := []MACEntry{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := macEntryRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		rest := strings.Fields(matches[4])
+		entry := MACEntry{
+			VLAN:      matches[1],
+			MAC:       strings.ToLower(matches[2]),
+			Type:      normalizeMACType(matches[3]),
+			Interface: rest[len(rest)-1],
+		}
+		if ostype == rpc.NXOS && len(rest) >= 3 && rest[1] == "T" {
+			entry.Type = "secure"
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func normalizeMACType(t string) string {
+	t = strings.ToLower(t)
+	if strings.Contains(t, "secure") {
+		return "secure"
 	}
-	return total
-}
\ No newline at end of file
+	return t
+}
@@ -0,0 +1,156 @@
+package tables
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ndEntriesDesc          = prometheus.NewDesc(prefix+"ipv6_neighbors", "Number of IPv6 neighbor discovery entries", []string{"target"}, nil)
+	ndEntriesVRFDesc       = prometheus.NewDesc(prefix+"ipv6_neighbors_vrf", "Number of IPv6 neighbor discovery entries per VRF", []string{"target", "vrf"}, nil)
+	ndEntriesInterfaceDesc = prometheus.NewDesc(prefix+"ipv6_neighbors_interface", "Number of IPv6 neighbor discovery entries per VRF and interface", []string{"target", "vrf", "interface"}, nil)
+	ndIncompleteDesc       = prometheus.NewDesc(prefix+"ipv6_neighbors_incomplete", "Number of incomplete IPv6 neighbor discovery entries per VRF", []string{"target", "vrf"}, nil)
+
+	ndIOSRegexp  = regexp.MustCompile(`^([0-9A-Fa-f:]*:[0-9A-Fa-f:.]*)\s+\S+\s+(\S+)\s+(\S+)\s+(\S+)`)
+	ndNXOSRegexp = regexp.MustCompile(`^([0-9A-Fa-f:]*:[0-9A-Fa-f:.]*)\s+\S+\s+(\S+)\s+\S+\s+\S+\s+(\S+)`)
+	// NX-OS prints addresses too long for the column on a line of their own, followed by the rest of the entry
+	ndNXOSAddressRegexp      = regexp.MustCompile(`^([0-9A-Fa-f:]*:[0-9A-Fa-f:.]*)\s*$`)
+	ndNXOSContinuationRegexp = regexp.MustCompile(`^\s+\S+\s+(\S+)\s+\S+\s+\S+\s+(\S+)`)
+)
+
+// NDEntry is a single entry of the IPv6 neighbor table
+type NDEntry struct {
+	VRF        string
+	Address    string
+	MAC        string
+	State      string
+	Interface  string
+	Incomplete bool
+}
+
+type ndCollector struct{}
+
+// NewNDCollector creates a collector for the IPv6 neighbor discovery table
+func NewNDCollector() collector.RPCCollector {
+	return &ndCollector{}
+}
+
+func (*ndCollector) Name() string {
+	return "TablesND"
+}
+
+func (*ndCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ndEntriesDesc
+	ch <- ndEntriesVRFDesc
+	ch <- ndEntriesInterfaceDesc
+	ch <- ndIncompleteDesc
+}
+
+func (c *ndCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	entries := []NDEntry{}
+	vrfs := vrfsForClient(client)
+	for _, vrf := range vrfs {
+		cmd := "show ipv6 neighbors"
+		if vrf != defaultVRF {
+			cmd += " vrf " + vrf
+		}
+		out, err := client.RunCommand(cmd)
+		if err != nil {
+			if vrf == defaultVRF {
+				return err
+			}
+			if client.Debug {
+				log.Printf("ND command for vrf %s on %s: %s\n", vrf, labelValues[0], err.Error())
+			}
+			continue
+		}
+		items, err := ParseND(client.OSType, vrf, out)
+		if err != nil {
+			if client.Debug {
+				log.Printf("ParseND for %s: %s\n", labelValues[0], err.Error())
+			}
+			return nil
+		}
+		entries = append(entries, items...)
+	}
+
+	perVRF := make(map[string]float64)
+	incomplete := make(map[string]float64)
+	perInterface := make(map[[2]string]float64)
+	for _, vrf := range vrfs {
+		perVRF[vrf] = 0
+		incomplete[vrf] = 0
+	}
+	for _, e := range entries {
+		perVRF[e.VRF]++
+		if e.Incomplete {
+			incomplete[e.VRF]++
+		}
+		perInterface[[2]string{e.VRF, e.Interface}]++
+	}
+
+	ch <- prometheus.MustNewConstMetric(ndEntriesDesc, prometheus.GaugeValue, float64(len(entries)), labelValues...)
+	for vrf, count := range perVRF {
+		ch <- prometheus.MustNewConstMetric(ndEntriesVRFDesc, prometheus.GaugeValue, count, append(labelValues, vrf)...)
+		ch <- prometheus.MustNewConstMetric(ndIncompleteDesc, prometheus.GaugeValue, incomplete[vrf], append(labelValues, vrf)...)
+	}
+	for k, count := range perInterface {
+		ch <- prometheus.MustNewConstMetric(ndEntriesInterfaceDesc, prometheus.GaugeValue, count, append(labelValues, k[0], k[1])...)
+	}
+	return nil
+}
+
+// ParseND parses the output of 'show ipv6 neighbors [vrf X]' and returns all entries of the table
+func ParseND(ostype string, vrf string, output string) ([]NDEntry, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return nil, errors.New("'show ipv6 neighbors' is not implemented for " + ostype)
+	}
+	entries := []NDEntry{}
+	wrapped := ""
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if ostype == rpc.NXOS {
+			line = strings.TrimRight(line, "\r")
+			address, mac, iface := "", "", ""
+			if matches := ndNXOSRegexp.FindStringSubmatch(line); matches != nil {
+				address, mac, iface = matches[1], matches[2], matches[3]
+			} else if matches := ndNXOSAddressRegexp.FindStringSubmatch(line); matches != nil {
+				wrapped = matches[1]
+				continue
+			} else if matches := ndNXOSContinuationRegexp.FindStringSubmatch(line); matches != nil && wrapped != "" {
+				address, mac, iface = wrapped, matches[1], matches[2]
+			}
+			wrapped = ""
+			if address == "" {
+				continue
+			}
+			entries = append(entries, NDEntry{
+				VRF:        vrf,
+				Address:    address,
+				MAC:        mac,
+				Interface:  iface,
+				Incomplete: strings.EqualFold(mac, "incomplete"),
+			})
+			continue
+		}
+		matches := ndIOSRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		entries = append(entries, NDEntry{
+			VRF:        vrf,
+			Address:    matches[1],
+			MAC:        matches[2],
+			State:      matches[3],
+			Interface:  matches[4],
+			Incomplete: matches[3] == "INCMP",
+		})
+	}
+	return entries, nil
+}
@@ -1,21 +1,22 @@
 package tables
 
 import (
-	"regexp"
 	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/config"
 	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
-	"github.com/moeinshahcheraghi/cisco_exporter/util"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
-	routesIPv6Desc = prometheus.NewDesc(prefix+"routes_ipv6", "Number of IPv6 routes", []string{"target"}, nil)
+	routesIPv6Descs = newRouteDescs("ipv6", "IPv6")
 )
 
-type routeIPv6Collector struct{}
+type routeIPv6Collector struct {
+	watch []*config.RouteWatchConfig
+}
 
-func NewRouteIPv6Collector() collector.RPCCollector {
-	return &routeIPv6Collector{}
+func NewRouteIPv6Collector(watch []*config.RouteWatchConfig) collector.RPCCollector {
+	return &routeIPv6Collector{watch: watch}
 }
 
 func (*routeIPv6Collector) Name() string {
@@ -23,24 +24,16 @@ func (*routeIPv6Collector) Name() string {
 }
 
 func (*routeIPv6Collector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- routesIPv6Desc
+	routesIPv6Descs.describe(ch)
+	ch <- routeWatchPresentDesc
+	ch <- routeWatchInfoDesc
 }
 
 func (c *routeIPv6Collector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
-	out, err := client.RunCommand("show ipv6 route summary")
+	err := collectRouteSummaries(client, ch, labelValues, true, routesIPv6Descs)
 	if err != nil {
 		return err
 	}
-	routeCount := parseRoutesIPv6(out)
-	ch <- prometheus.MustNewConstMetric(routesIPv6Desc, prometheus.GaugeValue, routeCount, labelValues...)
+	collectRouteWatch(client, ch, labelValues, c.watch, true)
 	return nil
 }
-
-func parseRoutesIPv6(output string) float64 {
-	re := regexp.MustCompile(`Total\s+(\d+)`)
-	matches := re.FindStringSubmatch(output)
-	if matches != nil {
-		return util.Str2float64(matches[1])
-	}
-	return 0
-}
\ No newline at end of file
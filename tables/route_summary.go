@@ -0,0 +1,225 @@
+package tables
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/moeinshahcheraghi/cisco_exporter/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	nxosRouteTotalRegexp  = regexp.MustCompile(`^\s*Total number of routes:\s*(\d+)`)
+	nxosRouteSourceRegexp = regexp.MustCompile(`^\s+(\S+)\s*:\s*(\d+)`)
+)
+
+// RouteSource holds the number of routes and the memory used by one route source
+type RouteSource struct {
+	Protocol  string
+	Process   string
+	Routes    float64
+	Memory    float64
+	HasMemory bool
+}
+
+// RouteSummary holds the route counts of one routing table
+type RouteSummary struct {
+	VRF     string
+	Total   float64
+	Sources []RouteSource
+}
+
+// routeSummaryCommand returns the route summary command for an OS type, address family and VRF
+func routeSummaryCommand(ostype string, ipv6 bool, vrf string) string {
+	afi := "ip"
+	if ipv6 {
+		afi = "ipv6"
+	}
+	if vrf == defaultVRF {
+		return "show " + afi + " route summary"
+	}
+	if ostype == rpc.NXOS {
+		return "show " + afi + " route summary vrf " + vrf
+	}
+	return "show " + afi + " route vrf " + vrf + " summary"
+}
+
+// ParseRouteSummary parses the output of 'show ip route summary' / 'show ipv6 route summary'
+// and returns the number of routes (and memory usage where reported) per route source
+func ParseRouteSummary(ostype string, vrf string, output string) (RouteSummary, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return RouteSummary{}, errors.New("'show ip route summary' is not implemented for " + ostype)
+	}
+	if ostype == rpc.NXOS {
+		return parseRouteSummaryNXOS(vrf, output), nil
+	}
+	return parseRouteSummaryIOS(vrf, output), nil
+}
+
+func parseRouteSummaryIOS(vrf string, output string) RouteSummary {
+	summary := RouteSummary{VRF: vrf}
+	var columns []string
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) > 2 && fields[0] == "Route" && fields[1] == "Source" {
+			columns = []string{}
+			// the unit is a token of its own in 'Memory (bytes)' but not in 'Memory(bytes)'
+			for _, f := range fields[2:] {
+				if i := strings.Index(f, "("); i >= 0 {
+					f = f[:i]
+				}
+				if f != "" {
+					columns = append(columns, f)
+				}
+			}
+			continue
+		}
+		if columns == nil || len(fields) < 2 || strings.Contains(line, ":") {
+			continue
+		}
+
+		numeric := 0
+		for i := len(fields) - 1; i >= 0; i-- {
+			if _, err := strconv.ParseUint(fields[i], 10, 64); err != nil {
+				break
+			}
+			numeric++
+		}
+		if numeric == 0 || numeric == len(fields) {
+			continue
+		}
+
+		values := make(map[string]float64)
+		source := fields[:len(fields)-numeric]
+		if numeric >= len(columns) {
+			source = fields[:len(fields)-len(columns)]
+			for i, col := range columns {
+				values[col] = util.Str2float64(fields[len(source)+i])
+			}
+		} else {
+			values[columns[0]] = util.Str2float64(fields[len(source)])
+			if numeric > 1 {
+				values[columns[len(columns)-1]] = util.Str2float64(fields[len(fields)-1])
+			}
+		}
+
+		routes := values["Networks"] + values["Subnets"] + values["Number"]
+		if source[0] == "Total" {
+			summary.Total = routes
+			continue
+		}
+		memory, hasMemory := values["Memory"]
+		summary.Sources = append(summary.Sources, RouteSource{
+			Protocol:  source[0],
+			Process:   strings.Join(source[1:], " "),
+			Routes:    routes,
+			Memory:    memory,
+			HasMemory: hasMemory,
+		})
+	}
+	return summary
+}
+
+func parseRouteSummaryNXOS(vrf string, output string) RouteSummary {
+	summary := RouteSummary{VRF: vrf}
+	inSources := false
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if matches := nxosRouteTotalRegexp.FindStringSubmatch(line); matches != nil {
+			summary.Total = util.Str2float64(matches[1])
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "Best paths per protocol") {
+			inSources = true
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "Number of routes per mask-length") {
+			inSources = false
+			continue
+		}
+		if !inSources {
+			continue
+		}
+		matches := nxosRouteSourceRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		source := RouteSource{
+			Protocol: matches[1],
+			Routes:   util.Str2float64(matches[2]),
+		}
+		if i := strings.Index(source.Protocol, "-"); i > 0 {
+			source.Process = source.Protocol[i+1:]
+			source.Protocol = source.Protocol[:i]
+		}
+		summary.Sources = append(summary.Sources, source)
+	}
+	return summary
+}
+
+type routeDescs struct {
+	total  *prometheus.Desc
+	vrf    *prometheus.Desc
+	source *prometheus.Desc
+	memory *prometheus.Desc
+}
+
+func newRouteDescs(afi string, name string) routeDescs {
+	l := []string{"target", "vrf"}
+	return routeDescs{
+		total:  prometheus.NewDesc(prefix+"routes_"+afi, "Number of "+name+" routes", []string{"target"}, nil),
+		vrf:    prometheus.NewDesc(prefix+"routes_"+afi+"_vrf", "Number of "+name+" routes per VRF", l, nil),
+		source: prometheus.NewDesc(prefix+"routes_"+afi+"_source", "Number of "+name+" routes per VRF and route source", append(l, "protocol", "process"), nil),
+		memory: prometheus.NewDesc(prefix+"routes_"+afi+"_memory_bytes", "Memory used by "+name+" routes per VRF and route source in bytes", append(l, "protocol", "process"), nil),
+	}
+}
+
+func (d routeDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- d.total
+	ch <- d.vrf
+	ch <- d.source
+	ch <- d.memory
+}
+
+// collectRouteSummaries exports the route summary of every VRF for one address family
+func collectRouteSummaries(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string, ipv6 bool, d routeDescs) error {
+	for _, vrf := range vrfsForClient(client) {
+		out, err := client.RunCommand(routeSummaryCommand(client.OSType, ipv6, vrf))
+		if err != nil {
+			if vrf == defaultVRF {
+				return err
+			}
+			if client.Debug {
+				log.Printf("Route summary for vrf %s on %s: %s\n", vrf, labelValues[0], err.Error())
+			}
+			continue
+		}
+		summary, err := ParseRouteSummary(client.OSType, vrf, out)
+		if err != nil {
+			if client.Debug {
+				log.Printf("ParseRouteSummary for %s: %s\n", labelValues[0], err.Error())
+			}
+			return nil
+		}
+
+		if vrf == defaultVRF {
+			ch <- prometheus.MustNewConstMetric(d.total, prometheus.GaugeValue, summary.Total, labelValues...)
+		}
+		l := append(labelValues, vrf)
+		ch <- prometheus.MustNewConstMetric(d.vrf, prometheus.GaugeValue, summary.Total, l...)
+		for _, s := range summary.Sources {
+			ch <- prometheus.MustNewConstMetric(d.source, prometheus.GaugeValue, s.Routes, append(l, s.Protocol, s.Process)...)
+			if s.HasMemory {
+				ch <- prometheus.MustNewConstMetric(d.memory, prometheus.GaugeValue, s.Memory, append(l, s.Protocol, s.Process)...)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package tables
+
+import (
+	"testing"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+)
+
+const ipRouteSummaryIOS = `IP routing table name is default (0x0)
+IP routing table maximum-paths is 32
+Route Source    Networks    Subnets     Replicates  Overhead    Memory (bytes)
+connected       0           4           0           384         1216
+static          1           0           0           96          304
+application     0           0           0           0           0
+ospf 1          2           10          0           1152        3648
+  Intra-area: 4 Inter-area: 8 External-1: 0 External-2: 0
+  NSSA External-1: 0 NSSA External-2: 0
+internal        3                                               1368
+Total           6           14          0           1632        6536
+`
+
+const ipv6RouteSummaryIOS = `IPv6 routing table name is default(0) global scope - 7 entries
+IPv6 routing table default maximum-paths is 16
+Route Source    Networks    Overhead    Memory(bytes)
+connected       2           224         272
+local           3           336         408
+application     0           0           0
+static          1           112         136
+ospf 1          1           112         136
+  Intra-area: 1 Inter-area: 0 External: 0 NSSA: 0
+Total           7           784         952
+
+Number of prefixes:
+  /0: 1, /64: 2, /128: 4
+`
+
+func TestParseRouteSummaryIOS(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		total    float64
+		expected []RouteSource
+	}{
+		{
+			name:   "ipv4",
+			output: ipRouteSummaryIOS,
+			total:  20,
+			expected: []RouteSource{
+				{Protocol: "connected", Routes: 4, Memory: 1216, HasMemory: true},
+				{Protocol: "static", Routes: 1, Memory: 304, HasMemory: true},
+				{Protocol: "application", Routes: 0, Memory: 0, HasMemory: true},
+				{Protocol: "ospf", Process: "1", Routes: 12, Memory: 3648, HasMemory: true},
+				{Protocol: "internal", Routes: 3, Memory: 1368, HasMemory: true},
+			},
+		},
+		{
+			name:   "ipv6",
+			output: ipv6RouteSummaryIOS,
+			total:  7,
+			expected: []RouteSource{
+				{Protocol: "connected", Routes: 2, Memory: 272, HasMemory: true},
+				{Protocol: "local", Routes: 3, Memory: 408, HasMemory: true},
+				{Protocol: "application", Routes: 0, Memory: 0, HasMemory: true},
+				{Protocol: "static", Routes: 1, Memory: 136, HasMemory: true},
+				{Protocol: "ospf", Process: "1", Routes: 1, Memory: 136, HasMemory: true},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			summary, err := ParseRouteSummary(rpc.IOSXE, defaultVRF, test.output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if summary.Total != test.total {
+				t.Errorf("expected %v routes in total, got %v", test.total, summary.Total)
+			}
+			if len(summary.Sources) != len(test.expected) {
+				t.Fatalf("expected %d route sources, got %+v", len(test.expected), summary.Sources)
+			}
+			for i, expected := range test.expected {
+				if summary.Sources[i] != expected {
+					t.Errorf("expected %+v, got %+v", expected, summary.Sources[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package tables
+
+import (
+	"errors"
+	"log"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/config"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	routeWatchPresentDesc = prometheus.NewDesc(prefix+"route_watch_present", "Watched prefix is present in the routing table (1 present, 0 missing)", []string{"target", "vrf", "prefix"}, nil)
+	routeWatchInfoDesc    = prometheus.NewDesc(prefix+"route_watch_info", "Protocol and next-hop of a watched prefix", []string{"target", "vrf", "prefix", "protocol", "next_hop"}, nil)
+
+	routeEntryRegexp     = regexp.MustCompile(`^\s*Routing entry for (\S+)`)
+	routeKnownViaRegexp  = regexp.MustCompile(`^\s*Known via "([^" ]+)`)
+	routeNextHopRegexp   = regexp.MustCompile(`^\s*\*?\s*([0-9A-Fa-f]*[.:][0-9A-Fa-f.:]*)(?:,|\s*$)`)
+	routeConnectedRegexp = regexp.MustCompile(`directly connected,? via (\S+?),?\s*$`)
+	nxosRouteEntryRegexp = regexp.MustCompile(`^(\S+/\d+), ubest/mbest`)
+	nxosRouteViaRegexp   = regexp.MustCompile(`^\s*\*via ([0-9A-Fa-f.:]+)(?:%\S+)?, (?:(\S+), )?\[\d+/\d+\], [^,]+, ([\w-]+)`)
+)
+
+// RouteLookup is the result of looking up a watched prefix in the routing table
+type RouteLookup struct {
+	Present  bool
+	Protocol string
+	NextHops []string
+}
+
+// routeLookupCommand returns the command to look up an exact prefix for an OS type
+func routeLookupCommand(ostype string, ipnet *net.IPNet, vrf string) string {
+	afi := "ip"
+	if ipnet.IP.To4() == nil {
+		afi = "ipv6"
+	}
+	if ostype == rpc.NXOS {
+		cmd := "show " + afi + " route " + ipnet.String()
+		if vrf != defaultVRF {
+			cmd += " vrf " + vrf
+		}
+		return cmd
+	}
+	cmd := "show " + afi + " route "
+	if vrf != defaultVRF {
+		cmd += "vrf " + vrf + " "
+	}
+	if afi == "ipv6" {
+		return cmd + ipnet.String()
+	}
+	return cmd + ipnet.IP.String() + " " + net.IP(ipnet.Mask).String()
+}
+
+// collectRouteWatch exports presence, protocol and next-hops of the watched prefixes of one address family
+func collectRouteWatch(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string, watches []*config.RouteWatchConfig, ipv6 bool) {
+	for _, w := range watches {
+		_, ipnet, err := net.ParseCIDR(w.Prefix)
+		if err != nil {
+			if client.Debug {
+				log.Printf("Invalid watched prefix %s for %s: %s\n", w.Prefix, labelValues[0], err.Error())
+			}
+			continue
+		}
+		if (ipnet.IP.To4() == nil) != ipv6 {
+			continue
+		}
+		vrf := w.VRF
+		if vrf == "" {
+			vrf = defaultVRF
+		}
+
+		out, err := client.RunCommand(routeLookupCommand(client.OSType, ipnet, vrf))
+		if err != nil {
+			if client.Debug {
+				log.Printf("Route lookup of %s on %s: %s\n", w.Prefix, labelValues[0], err.Error())
+			}
+			continue
+		}
+		lookup, err := ParseRouteLookup(client.OSType, ipnet, out)
+		if err != nil {
+			if client.Debug {
+				log.Printf("ParseRouteLookup for %s: %s\n", labelValues[0], err.Error())
+			}
+			continue
+		}
+
+		l := append(labelValues, vrf, w.Prefix)
+		present := 0.0
+		if lookup.Present {
+			present = 1
+		}
+		ch <- prometheus.MustNewConstMetric(routeWatchPresentDesc, prometheus.GaugeValue, present, l...)
+		for _, nh := range lookup.NextHops {
+			ch <- prometheus.MustNewConstMetric(routeWatchInfoDesc, prometheus.GaugeValue, 1, append(l, lookup.Protocol, nh)...)
+		}
+	}
+}
+
+// ParseRouteLookup parses the output of 'show ip route <prefix>' and reports whether exactly the
+// requested prefix is present, which protocol installed it and its next-hops
+func ParseRouteLookup(ostype string, ipnet *net.IPNet, output string) (RouteLookup, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return RouteLookup{}, errors.New("'show ip route' is not implemented for " + ostype)
+	}
+	lookup := RouteLookup{}
+	seen := make(map[string]bool)
+	addNextHop := func(nh string) {
+		if !seen[nh] {
+			seen[nh] = true
+			lookup.NextHops = append(lookup.NextHops, nh)
+		}
+	}
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		if ostype == rpc.NXOS {
+			if matches := nxosRouteEntryRegexp.FindStringSubmatch(line); matches != nil {
+				lookup.Present = samePrefix(matches[1], ipnet)
+			} else if matches := nxosRouteViaRegexp.FindStringSubmatch(line); matches != nil && lookup.Present {
+				lookup.Protocol = strings.SplitN(matches[3], "-", 2)[0]
+				addNextHop(matches[1])
+			}
+			continue
+		}
+
+		if matches := routeEntryRegexp.FindStringSubmatch(line); matches != nil {
+			lookup.Present = samePrefix(matches[1], ipnet) && !strings.Contains(line, "known subnets")
+		} else if !lookup.Present {
+			continue
+		} else if matches := routeKnownViaRegexp.FindStringSubmatch(line); matches != nil {
+			lookup.Protocol = matches[1]
+		} else if matches := routeConnectedRegexp.FindStringSubmatch(line); matches != nil {
+			addNextHop(matches[1])
+		} else if matches := routeNextHopRegexp.FindStringSubmatch(line); matches != nil && net.ParseIP(matches[1]) != nil {
+			addNextHop(matches[1])
+		}
+	}
+	if !lookup.Present {
+		return RouteLookup{}, nil
+	}
+	return lookup, nil
+}
+
+func samePrefix(prefix string, ipnet *net.IPNet) bool {
+	_, parsed, err := net.ParseCIDR(strings.TrimSuffix(prefix, ","))
+	if err != nil {
+		return false
+	}
+	return parsed.String() == ipnet.String()
+}
@@ -0,0 +1,52 @@
+package tables
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+)
+
+const defaultVRF = "default"
+
+// vrfCommand returns the command listing the configured VRFs for an OS type
+func vrfCommand(ostype string) string {
+	if ostype == rpc.IOS {
+		return "show ip vrf"
+	}
+	return "show vrf"
+}
+
+// ParseVRFs parses the output of 'show vrf' / 'show ip vrf' and returns the names of all non-default VRFs
+func ParseVRFs(ostype string, output string) ([]string, error) {
+	if ostype != rpc.IOSXE && ostype != rpc.NXOS && ostype != rpc.IOS {
+		return nil, errors.New("'show vrf' is not implemented for " + ostype)
+	}
+	vrfs := []string{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "%") {
+			continue
+		}
+		if fields[0] == "show" || fields[0] == "Name" || fields[0] == "VRF-Name" || fields[0] == defaultVRF || strings.HasSuffix(fields[0], "#") {
+			continue
+		}
+		vrfs = append(vrfs, fields[0])
+	}
+	return vrfs, nil
+}
+
+// vrfsForClient returns the default VRF followed by all other VRFs configured on the device
+func vrfsForClient(client *rpc.Client) []string {
+	vrfs := []string{defaultVRF}
+	out, err := client.RunCommand(vrfCommand(client.OSType))
+	if err != nil {
+		return vrfs
+	}
+	names, err := ParseVRFs(client.OSType, out)
+	if err != nil {
+		return vrfs
+	}
+	return append(vrfs, names...)
+}
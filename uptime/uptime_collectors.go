@@ -3,10 +3,9 @@ package uptime
 import (
     "errors"
     "regexp"
-    "strings"
-    "strconv"
     "github.com/moeinshahcheraghi/cisco_exporter/collector"
     "github.com/moeinshahcheraghi/cisco_exporter/rpc"
+    "github.com/moeinshahcheraghi/cisco_exporter/util"
     "github.com/prometheus/client_golang/prometheus"
 )
 
@@ -48,34 +47,10 @@ func (c *uptimeCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metri
 }
 
 func parseUptime(output string) (float64, error) {
-    re := regexp.MustCompile(`uptime is ([\w\s,]+)`)
+    re := regexp.MustCompile(`(?m)uptime is ([^\r\n]+)`)
     matches := re.FindStringSubmatch(output)
     if matches == nil {
         return 0, errors.New("uptime not found in output")
     }
-    return convertUptimeToSeconds(matches[1]), nil
+    return util.Duration2Seconds(matches[1]), nil
 }
-
-func convertUptimeToSeconds(uptimeStr string) float64 {
-    var totalSeconds float64
-    parts := strings.Split(uptimeStr, ", ")
-    for _, part := range parts {
-        fields := strings.Fields(part)
-        if len(fields) < 2 {
-            continue
-        }
-        num, _ := strconv.ParseFloat(fields[0], 64)
-        unit := fields[1]
-        switch {
-        case strings.HasPrefix(unit, "week"):
-            totalSeconds += num * 7 * 24 * 60 * 60
-        case strings.HasPrefix(unit, "day"):
-            totalSeconds += num * 24 * 60 * 60
-        case strings.HasPrefix(unit, "hour"):
-            totalSeconds += num * 60 * 60
-        case strings.HasPrefix(unit, "minute"):
-            totalSeconds += num * 60
-        }
-    }
-    return totalSeconds
-}
\ No newline at end of file
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Duration2Seconds converts a duration as printed by Cisco devices
+// (e.g. "1 year, 2 weeks, 3 days, 4 hours, 5 minutes" or "52 days 3 hours") to seconds
+func Duration2Seconds(str string) float64 {
+	var seconds float64
+	fields := strings.Fields(str)
+	for i := 0; i+1 < len(fields); i++ {
+		num, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			continue
+		}
+		unit := fields[i+1]
+		switch {
+		case strings.HasPrefix(unit, "year"):
+			seconds += num * 365 * 24 * 60 * 60
+		case strings.HasPrefix(unit, "week"):
+			seconds += num * 7 * 24 * 60 * 60
+		case strings.HasPrefix(unit, "day"):
+			seconds += num * 24 * 60 * 60
+		case strings.HasPrefix(unit, "hour"):
+			seconds += num * 60 * 60
+		case strings.HasPrefix(unit, "min"):
+			seconds += num * 60
+		case strings.HasPrefix(unit, "sec"):
+			seconds += num
+		}
+	}
+	return seconds
+}
@@ -0,0 +1,73 @@
+package wlc
+
+import (
+	"log"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var channelUtilizationDesc *prometheus.Desc
+
+func init() {
+	l := []string{"target", "ap", "band"}
+	channelUtilizationDesc = prometheus.NewDesc(prefix+"radio_channel_utilization_percent", "Utilization of the channel of a radio in percent", l, nil)
+}
+
+type channelCollector struct{}
+
+// NewChannelCollector creates a new collector for the channel utilization, which needs two commands per access point
+func NewChannelCollector() collector.RPCCollector {
+	return &channelCollector{}
+}
+
+// Name returns the name of the collector
+func (*channelCollector) Name() string {
+	return "WLCChannelUtilization"
+}
+
+// Describe describes the metrics
+func (*channelCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- channelUtilizationDesc
+}
+
+// Collect collects metrics from Cisco
+func (c *channelCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	if !isWLC(client) {
+		return nil
+	}
+	out, err := client.RunCommand("show ap summary")
+	if err != nil {
+		return err
+	}
+	aps, err := ParseAPs(client.OSType, out)
+	if err != nil {
+		if client.Debug {
+			log.Printf("ParseAPs for %s: %s\n", labelValues[0], err.Error())
+		}
+		return nil
+	}
+	for _, ap := range aps {
+		for _, band := range bands {
+			cmd := "show ap auto-rf " + ieeeBand(band) + " " + ap.Name
+			if client.OSType == rpc.IOSXE {
+				cmd = "show ap name " + ap.Name + " auto-rf dot11 " + dot11Band(band)
+			}
+			out, err := client.RunCommand(cmd)
+			if err != nil {
+				if client.Debug {
+					log.Printf("Auto-RF command on %s: %s\n", labelValues[0], err.Error())
+				}
+				continue
+			}
+			utilization, err := ParseChannelUtilization(client.OSType, out)
+			if err != nil {
+				// APs without a radio in the band
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(channelUtilizationDesc, prometheus.GaugeValue, utilization, append(labelValues, ap.Name, band)...)
+		}
+	}
+	return nil
+}
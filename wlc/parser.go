@@ -0,0 +1,169 @@
+package wlc
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/moeinshahcheraghi/cisco_exporter/util"
+)
+
+// ParseAPs parses the output of 'show ap summary'
+func ParseAPs(ostype string, output string) ([]AccessPoint, error) {
+	if ostype != rpc.AIREOS && ostype != rpc.IOSXE {
+		return nil, errors.New("'show ap summary' is not implemented for " + ostype)
+	}
+	// the location may contain blanks, it is delimited by the MAC address and the country code
+	apRegexp := regexp.MustCompile(`^(\S+)\s+\d+\s+(\S+)\s+([0-9a-f:]{17})\s+(.*?)\s+\S+\s+(\S+)\s+\d+(?:\s+\[.*\])?\s*$`)
+	if ostype == rpc.IOSXE {
+		apRegexp = regexp.MustCompile(`^(\S+)\s+\d+\s+(\S+)\s+([0-9a-f.]{14})\s+(?:[0-9a-f.]{14}\s+)?(.*?)\s+\S+\s+(\S+)\s+\S+\s*$`)
+	}
+
+	items := []AccessPoint{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := apRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		items = append(items, AccessPoint{
+			Name:     matches[1],
+			Model:    matches[2],
+			MAC:      matches[3],
+			Location: matches[4],
+			IP:       matches[5],
+		})
+	}
+	return items, nil
+}
+
+// ParseAPJoin parses the output of 'show ap join stats summary all' (AireOS) or 'show wireless stats ap join summary' (9800)
+func ParseAPJoin(ostype string, output string) ([]APJoin, error) {
+	if ostype != rpc.AIREOS && ostype != rpc.IOSXE {
+		return nil, errors.New("'show ap join stats summary' is not implemented for " + ostype)
+	}
+	joinRegexp := regexp.MustCompile(`^[0-9a-f:.]{14,17}\s+[0-9a-f:.]{14,17}\s+(\S+)\s+(\S+)\s+(Joined|Not Joined)\b`)
+
+	items := []APJoin{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := joinRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		items = append(items, APJoin{
+			Name:   matches[1],
+			IP:     matches[2],
+			Joined: matches[3] == "Joined",
+		})
+	}
+	return items, nil
+}
+
+// ParseClients parses the output of 'show client summary' (AireOS) or 'show wireless client summary' (9800)
+func ParseClients(ostype string, output string) ([]WirelessClient, error) {
+	if ostype != rpc.AIREOS && ostype != rpc.IOSXE {
+		return nil, errors.New("'show client summary' is not implemented for " + ostype)
+	}
+	clientRegexp := regexp.MustCompile(`^([0-9a-f:]{17})\s+(\S+)\s+(?:\d+|N/A)\s+\S+\s+(\d+)\s`)
+	if ostype == rpc.IOSXE {
+		clientRegexp = regexp.MustCompile(`^([0-9a-f.]{14})\s+(\S+)\s+WLAN\s+(\d+)\s`)
+	}
+
+	items := []WirelessClient{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := clientRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		items = append(items, WirelessClient{
+			MAC:  matches[1],
+			AP:   matches[2],
+			WLAN: matches[3],
+		})
+	}
+	return items, nil
+}
+
+// ParseWLANs parses the output of 'show wlan summary'
+func ParseWLANs(ostype string, output string) ([]WLAN, error) {
+	if ostype != rpc.AIREOS && ostype != rpc.IOSXE {
+		return nil, errors.New("'show wlan summary' is not implemented for " + ostype)
+	}
+	wlanRegexp := regexp.MustCompile(`^(\d+)\s+(.+?) / (.+?)\s+(Enabled|Disabled)\s`)
+	if ostype == rpc.IOSXE {
+		wlanRegexp = regexp.MustCompile(`^(\d+)\s+(\S+)\s+(.+?)\s+(UP|DOWN)\s`)
+	}
+
+	items := []WLAN{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := wlanRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		items = append(items, WLAN{
+			ID:      matches[1],
+			Profile: matches[2],
+			SSID:    matches[3],
+			Up:      matches[4] == "Enabled" || matches[4] == "UP",
+		})
+	}
+	return items, nil
+}
+
+// ParseRadios parses the output of 'show advanced 802.11{a|b} summary' (AireOS) or 'show ap dot11 {5ghz|24ghz} summary' (9800)
+func ParseRadios(ostype string, band string, output string) ([]Radio, error) {
+	if ostype != rpc.AIREOS && ostype != rpc.IOSXE {
+		return nil, errors.New("'show advanced summary' is not implemented for " + ostype)
+	}
+	radioRegexp := regexp.MustCompile(`^(\S+)\s+[0-9a-f:]{17}\s+(\d+)\s+(ENABLED|DISABLED)\s+(\S+)\s+(.*)$`)
+	// channel '36*' on AireOS, '(36)*' or '(36,40)*' on 9800, where the tx power in front is '*1/8 (23 dBm)'
+	channelRegexp := regexp.MustCompile(`^\S*?(\d+)`)
+	if ostype == rpc.IOSXE {
+		radioRegexp = regexp.MustCompile(`^(\S+)\s+[0-9a-f.]{14}\s+(\d+)\s+(Enabled|Disabled)\s+(\S+)\s+(.*)$`)
+		channelRegexp = regexp.MustCompile(`\((\d+)(?:,\d+)*\)`)
+	}
+
+	items := []Radio{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := radioRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		item := Radio{
+			AP:      matches[1],
+			Slot:    matches[2],
+			Band:    band,
+			AdminUp: strings.EqualFold(matches[3], "Enabled"),
+			Up:      strings.EqualFold(matches[4], "Up"),
+		}
+		if channel := channelRegexp.FindStringSubmatch(matches[5]); channel != nil {
+			item.Channel, item.HasChannel = util.Str2float64(channel[1]), true
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ParseChannelUtilization parses the channel utilization of a radio from the output of
+// 'show ap auto-rf' (AireOS) or 'show ap name auto-rf' (9800)
+func ParseChannelUtilization(ostype string, output string) (float64, error) {
+	if ostype != rpc.AIREOS && ostype != rpc.IOSXE {
+		return 0, errors.New("'show ap auto-rf' is not implemented for " + ostype)
+	}
+	utilizationRegexp := regexp.MustCompile(`^\s*Channel Utilization[.\s:]*(\d+)\s*%`)
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		matches := utilizationRegexp.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		return util.Str2float64(matches[1]), nil
+	}
+	return 0, errors.New("Channel utilization not found")
+}
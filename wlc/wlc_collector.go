@@ -0,0 +1,220 @@
+package wlc
+
+import (
+	"log"
+
+	"github.com/moeinshahcheraghi/cisco_exporter/collector"
+	"github.com/moeinshahcheraghi/cisco_exporter/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prefix string = "cisco_wlc_"
+
+var (
+	apInfoDesc       *prometheus.Desc
+	apJoinedDesc     *prometheus.Desc
+	apClientsDesc    *prometheus.Desc
+	wlanUpDesc       *prometheus.Desc
+	wlanClientsDesc  *prometheus.Desc
+	radioAdminUpDesc *prometheus.Desc
+	radioUpDesc      *prometheus.Desc
+	radioChannelDesc *prometheus.Desc
+)
+
+// bands are the radio bands of the access points as used in the band label
+var bands = []string{"2.4GHz", "5GHz"}
+
+func init() {
+	l := []string{"target"}
+	apInfoDesc = prometheus.NewDesc(prefix+"ap_info", "Access point joined to the controller", append(l, "ap", "model", "mac", "ip", "location"), nil)
+	apJoinedDesc = prometheus.NewDesc(prefix+"ap_joined", "Join status of an access point (1 Joined, 0 Not Joined)", append(l, "ap", "ip"), nil)
+	apClientsDesc = prometheus.NewDesc(prefix+"ap_clients", "Number of clients associated to an access point", append(l, "ap"), nil)
+	wlanUpDesc = prometheus.NewDesc(prefix+"wlan_up", "Status of a WLAN (1 Enabled, 0 Disabled)", append(l, "id", "profile", "ssid"), nil)
+	wlanClientsDesc = prometheus.NewDesc(prefix+"wlan_clients", "Number of clients associated to a WLAN", append(l, "id", "profile", "ssid"), nil)
+	l = append(l, "ap", "slot", "band")
+	radioAdminUpDesc = prometheus.NewDesc(prefix+"radio_admin_up", "Admin status of a radio (1 Enabled, 0 Disabled)", l, nil)
+	radioUpDesc = prometheus.NewDesc(prefix+"radio_up", "Operational status of a radio (1 Up, 0 Down)", l, nil)
+	radioChannelDesc = prometheus.NewDesc(prefix+"radio_channel", "Primary channel of a radio", l, nil)
+}
+
+type wlcCollector struct{}
+
+// NewCollector creates a new collector
+func NewCollector() collector.RPCCollector {
+	return &wlcCollector{}
+}
+
+// Name returns the name of the collector
+func (*wlcCollector) Name() string {
+	return "WLC"
+}
+
+// Describe describes the metrics
+func (*wlcCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- apInfoDesc
+	ch <- apJoinedDesc
+	ch <- apClientsDesc
+	ch <- wlanUpDesc
+	ch <- wlanClientsDesc
+	ch <- radioAdminUpDesc
+	ch <- radioUpDesc
+	ch <- radioChannelDesc
+}
+
+// CollectAPs collects the access points, their join status and number of clients
+func (c *wlcCollector) CollectAPs(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show ap summary")
+	if err != nil {
+		return err
+	}
+	aps, err := ParseAPs(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	clients, err := collectClients(client)
+	if err != nil {
+		return err
+	}
+	perAP := make(map[string]float64)
+	for _, cl := range clients {
+		perAP[cl.AP]++
+	}
+	for _, ap := range aps {
+		ch <- prometheus.MustNewConstMetric(apInfoDesc, prometheus.GaugeValue, 1, append(labelValues, ap.Name, ap.Model, ap.MAC, ap.IP, ap.Location)...)
+		ch <- prometheus.MustNewConstMetric(apClientsDesc, prometheus.GaugeValue, perAP[ap.Name], append(labelValues, ap.Name)...)
+	}
+
+	cmd := "show ap join stats summary all"
+	if client.OSType == rpc.IOSXE {
+		cmd = "show wireless stats ap join summary"
+	}
+	out, err = client.RunCommand(cmd)
+	if err != nil {
+		return err
+	}
+	joins, err := ParseAPJoin(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	for _, j := range joins {
+		ch <- prometheus.MustNewConstMetric(apJoinedDesc, prometheus.GaugeValue, boolValue(j.Joined), append(labelValues, j.Name, j.IP)...)
+	}
+	return nil
+}
+
+// CollectWLANs collects the status and number of clients of the WLANs
+func (c *wlcCollector) CollectWLANs(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	out, err := client.RunCommand("show wlan summary")
+	if err != nil {
+		return err
+	}
+	wlans, err := ParseWLANs(client.OSType, out)
+	if err != nil {
+		return err
+	}
+	clients, err := collectClients(client)
+	if err != nil {
+		return err
+	}
+	perWLAN := make(map[string]float64)
+	for _, cl := range clients {
+		perWLAN[cl.WLAN]++
+	}
+	for _, w := range wlans {
+		l := append(labelValues, w.ID, w.Profile, w.SSID)
+		ch <- prometheus.MustNewConstMetric(wlanUpDesc, prometheus.GaugeValue, boolValue(w.Up), l...)
+		ch <- prometheus.MustNewConstMetric(wlanClientsDesc, prometheus.GaugeValue, perWLAN[w.ID], l...)
+	}
+	return nil
+}
+
+// CollectRadios collects admin and operational status and channel of the radios
+func (c *wlcCollector) CollectRadios(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	for _, band := range bands {
+		out, err := client.RunCommand(radioCommand(client.OSType, band))
+		if err != nil {
+			return err
+		}
+		radios, err := ParseRadios(client.OSType, band, out)
+		if err != nil {
+			return err
+		}
+		for _, r := range radios {
+			l := append(labelValues, r.AP, r.Slot, r.Band)
+			ch <- prometheus.MustNewConstMetric(radioAdminUpDesc, prometheus.GaugeValue, boolValue(r.AdminUp), l...)
+			ch <- prometheus.MustNewConstMetric(radioUpDesc, prometheus.GaugeValue, boolValue(r.Up), l...)
+			if r.HasChannel {
+				ch <- prometheus.MustNewConstMetric(radioChannelDesc, prometheus.GaugeValue, r.Channel, l...)
+			}
+		}
+	}
+	return nil
+}
+
+// Collect collects metrics from Cisco
+func (c *wlcCollector) Collect(client *rpc.Client, ch chan<- prometheus.Metric, labelValues []string) error {
+	if !isWLC(client) {
+		return nil
+	}
+	err := c.CollectAPs(client, ch, labelValues)
+	if client.Debug && err != nil {
+		log.Printf("CollectAPs for %s: %s\n", labelValues[0], err.Error())
+	}
+	err = c.CollectWLANs(client, ch, labelValues)
+	if client.Debug && err != nil {
+		log.Printf("CollectWLANs for %s: %s\n", labelValues[0], err.Error())
+	}
+	err = c.CollectRadios(client, ch, labelValues)
+	if client.Debug && err != nil {
+		log.Printf("CollectRadios for %s: %s\n", labelValues[0], err.Error())
+	}
+	return nil
+}
+
+// isWLC reports whether the device is a wireless LAN controller, the commands don't exist on other devices
+func isWLC(client *rpc.Client) bool {
+	return client.OSType == rpc.AIREOS || client.Wireless
+}
+
+// collectClients runs the client summary, its output is cached for the other metrics of the scrape
+func collectClients(client *rpc.Client) ([]WirelessClient, error) {
+	cmd := "show client summary"
+	if client.OSType == rpc.IOSXE {
+		cmd = "show wireless client summary"
+	}
+	out, err := client.RunCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return ParseClients(client.OSType, out)
+}
+
+func radioCommand(ostype string, band string) string {
+	if ostype == rpc.IOSXE {
+		return "show ap dot11 " + dot11Band(band) + " summary"
+	}
+	return "show advanced " + ieeeBand(band) + " summary"
+}
+
+// dot11Band returns the band as used by the 9800 CLI
+func dot11Band(band string) string {
+	if band == "5GHz" {
+		return "5ghz"
+	}
+	return "24ghz"
+}
+
+// ieeeBand returns the band as used by the AireOS CLI
+func ieeeBand(band string) string {
+	if band == "5GHz" {
+		return "802.11a"
+	}
+	return "802.11b"
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
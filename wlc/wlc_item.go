@@ -0,0 +1,43 @@
+package wlc
+
+// AccessPoint is an access point joined to the controller
+type AccessPoint struct {
+	Name     string
+	Model    string
+	MAC      string
+	IP       string
+	Location string
+}
+
+// APJoin is the join status of an access point which tried to join the controller
+type APJoin struct {
+	Name   string
+	IP     string
+	Joined bool
+}
+
+// WirelessClient is a client associated to an access point
+type WirelessClient struct {
+	MAC  string
+	AP   string
+	WLAN string
+}
+
+// WLAN is a wireless LAN (SSID) configured on the controller
+type WLAN struct {
+	ID      string
+	Profile string
+	SSID    string
+	Up      bool
+}
+
+// Radio is a radio of an access point in one band
+type Radio struct {
+	AP         string
+	Slot       string
+	Band       string
+	AdminUp    bool
+	Up         bool
+	Channel    float64
+	HasChannel bool
+}